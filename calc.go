@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/DaveM7788/tourOfGo/calc"
+)
+
+// calcCmd works out the expression in its arguments, or starts a REPL when
+// there aren't any. one starting with a minus needs a -- in front or it
+// looks like a flag, $ tour calc -- -2^2
+func calcCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("calc", flag.ContinueOnError)
+	tree := fs.Bool("tree", false, "show how the expression was grouped instead of its value")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c := calc.New()
+	if fs.NArg() == 0 {
+		return c.REPL(os.Stdin, w)
+	}
+	expr := strings.Join(fs.Args(), " ")
+	if *tree {
+		n, err := calc.Parse(expr)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, n)
+		return nil
+	}
+	v, err := c.Eval(expr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%g\n", v)
+	return nil
+}
@@ -0,0 +1,95 @@
+package calc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Calc evaluates lines one after another, remembering variables between them
+type Calc struct {
+	Vars map[string]float64
+}
+
+// New makes a Calc that knows pi and e
+func New() *Calc {
+	return &Calc{Vars: map[string]float64{"pi": math.Pi, "e": math.E}}
+}
+
+// Eval works out one line. "name = expr" sets a variable as well. the answer
+// is also kept in ans for the next line
+func (c *Calc) Eval(line string) (float64, error) {
+	toks, err := lex(line)
+	if err != nil {
+		return 0, err
+	}
+	name := ""
+	if len(toks) > 2 && toks[0].kind == tIdent && toks[1].kind == tAssign {
+		if _, ok := funcs[toks[0].text]; ok {
+			return 0, errorf(toks[0].col, "%s is a function", toks[0].text)
+		}
+		name = toks[0].text
+		// parse what's after the = but keep the columns from the whole line
+		toks = toks[2:]
+	}
+	p := &parser{toks: toks}
+	n, err := p.expr(0)
+	if err != nil {
+		return 0, err
+	}
+	if t := p.peek(); t.kind != tEOF {
+		return 0, errorf(t.col, "unexpected %s", describe(t))
+	}
+	v, err := n.Eval(c.Vars)
+	if err != nil {
+		return 0, err
+	}
+	if name != "" {
+		c.Vars[name] = v
+	}
+	c.Vars["ans"] = v
+	return v, nil
+}
+
+// REPL reads lines from in and prints each answer to out, with a caret under
+// the column of any mistake. vars lists the variables, quit or the end of in
+// stops it
+func (c *Calc) REPL(in io.Reader, out io.Writer) error {
+	const prompt = "> "
+	sc := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, prompt)
+		if !sc.Scan() {
+			fmt.Fprintln(out)
+			return sc.Err()
+		}
+		line := sc.Text()
+		switch strings.TrimSpace(line) {
+		case "":
+			continue
+		case "quit", "exit":
+			return nil
+		case "vars":
+			for _, k := range slices.Sorted(maps.Keys(c.Vars)) {
+				fmt.Fprintf(out, "%s = %g\n", k, c.Vars[k])
+			}
+			continue
+		}
+		v, err := c.Eval(line)
+		var e *Error
+		switch {
+		case errors.As(err, &e):
+			// the prompt is in front of the line on screen, so shift by that
+			fmt.Fprintf(out, "%s^ %s\n", strings.Repeat(" ", len(prompt)+e.Col-1), e.Msg)
+		case err != nil:
+			fmt.Fprintln(out, err)
+		default:
+			fmt.Fprintf(out, "%g\n", v)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package calc
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Parse's String puts brackets round everything, which shows how it grouped
+func TestPrecedence(t *testing.T) {
+	for _, tt := range []struct{ in, want string }{
+		{"1 + 2 * 3", "(1 + (2 * 3))"},
+		{"1 * 2 + 3", "((1 * 2) + 3)"},
+		{"(1 + 2) * 3", "((1 + 2) * 3)"},
+		{"2 * 3 ^ 2", "(2 * (3 ^ 2))"},
+		{"1 + 2 * -3 ^ 2", "(1 + (2 * (-(3 ^ 2))))"},
+		{"-2 * 3", "((-2) * 3)"},
+		{"-2 ^ 2", "(-(2 ^ 2))"},
+		{"7 % 4 + 1", "((7 % 4) + 1)"},
+		{"sqrt(1 + 3) * 2", "(sqrt((1 + 3)) * 2)"},
+		{"max(1, 2 + 3)", "max(1, (2 + 3))"},
+	} {
+		n, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := n.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAssociativity(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+		v        float64
+	}{
+		// everything groups from the left
+		{"10 - 4 - 3", "((10 - 4) - 3)", 3},
+		{"8 / 2 / 2", "((8 / 2) / 2)", 2},
+		{"1 + 2 + 3", "((1 + 2) + 3)", 6},
+		{"17 % 10 % 4", "((17 % 10) % 4)", 3},
+		// except ^, which groups from the right like on paper
+		{"2 ^ 3 ^ 2", "(2 ^ (3 ^ 2))", 512},
+		{"--2", "(-(-2))", 2},
+	} {
+		n, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := n.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+		if v, err := New().Eval(tt.in); v != tt.v || err != nil {
+			t.Errorf("Eval(%q) = %g, %v, want %g", tt.in, v, err, tt.v)
+		}
+	}
+}
+
+func TestErrorColumns(t *testing.T) {
+	for _, tt := range []struct {
+		in  string
+		col int
+		msg string
+	}{
+		{"", 1, "expected a number, found the end"},
+		{")", 1, "expected a number, found \")\""},
+		{"1 +* 2", 4, "expected a number, found \"*\""},
+		{"(1 + 2", 7, "expected ), found the end"},
+		{"1 $ 2", 3, "unexpected '$'"},
+		{"1 2", 3, "unexpected \"2\""},
+		{"y", 1, "y isn't set"},
+		{"2 * (1 + y)", 10, "y isn't set"},
+		{"foo(1)", 1, "no function called foo"},
+		{"max(1, 2, 3)", 1, "max takes 2 arguments, not 3"},
+		{"1 + pow(2)", 5, "pow takes 2 arguments, not 1"},
+		// the columns count from the start of the line, = and all
+		{"x = 1 +", 8, "expected a number, found the end"},
+		{"sqrt = 2", 1, "sqrt is a function"},
+	} {
+		_, err := New().Eval(tt.in)
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Errorf("Eval(%q) = %v, want a *calc.Error", tt.in, err)
+			continue
+		}
+		if e.Col != tt.col || e.Msg != tt.msg {
+			t.Errorf("Eval(%q): col %d %q, want col %d %q", tt.in, e.Col, e.Msg, tt.col, tt.msg)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	c := New()
+	for _, tt := range []struct {
+		in   string
+		want float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"ans * 2", 14},
+		{"r = 2", 2},
+		{"pi * r ^ 2", math.Pi * 4},
+		{"hypot(3, 4)", 5},
+		{"sqrt(16) + abs(-1)", 5},
+		{"1.5e2 / 3", 50},
+	} {
+		got, err := c.Eval(tt.in)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", tt.in, err)
+		} else if math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("Eval(%q) = %g, want %g", tt.in, got, tt.want)
+		}
+	}
+	if v, err := c.Eval("1 / 0"); !math.IsInf(v, 1) || err != nil {
+		t.Errorf("1 / 0 = %g, %v, want +Inf like go", v, err)
+	}
+}
+
+func TestREPL(t *testing.T) {
+	in := "x = 2\nx ^ 10\n1 +* 2\nvars\nquit\nnever read\n"
+	var out strings.Builder
+	if err := New().REPL(strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+	// in a terminal the caret line starts under the echoed input, so it's
+	// indented past the prompt as well as to the column
+	want := "> 2\n> 1024\n>      ^ expected a number, found \"*\"\n> ans = 1024\ne = 2.718281828459045\npi = 3.141592653589793\nx = 2\n> "
+	if out.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", out.String(), want)
+	}
+}
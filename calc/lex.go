@@ -0,0 +1,123 @@
+package calc
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Error is a mistake in an expression. Col counts runes from 1, so a caret
+// can be drawn under it
+type Error struct {
+	Col int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Col, e.Msg)
+}
+
+func errorf(col int, format string, args ...any) *Error {
+	return &Error{Col: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+type kind int
+
+const (
+	tEOF kind = iota
+	tNum
+	tIdent
+	tOp
+	tLParen
+	tRParen
+	tComma
+	tAssign
+)
+
+type token struct {
+	kind kind
+	text string
+	col  int
+	num  float64
+}
+
+// lex splits s into tokens. operators are matched longest first against
+// whatever is registered, so adding ** would just work
+func lex(s string) ([]token, error) {
+	var toks []token
+	col := 1
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		start := col
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			col++
+			continue
+		case r >= '0' && r <= '9' || r == '.':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			// an exponent, 1e9 or 2.5E-3
+			if j < len(s) && (s[j] == 'e' || s[j] == 'E') {
+				k := j + 1
+				if k < len(s) && (s[k] == '+' || s[k] == '-') {
+					k++
+				}
+				if k < len(s) && s[k] >= '0' && s[k] <= '9' {
+					for k < len(s) && s[k] >= '0' && s[k] <= '9' {
+						k++
+					}
+					j = k
+				}
+			}
+			n, err := strconv.ParseFloat(s[i:j], 64)
+			if err != nil {
+				return nil, errorf(start, "bad number %q", s[i:j])
+			}
+			toks = append(toks, token{kind: tNum, text: s[i:j], col: start, num: n})
+			col += j - i
+			i = j
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+					break
+				}
+				j += size
+				col++
+			}
+			toks = append(toks, token{kind: tIdent, text: s[i:j], col: start})
+			i = j
+			continue
+		}
+
+		single := map[rune]kind{'(': tLParen, ')': tRParen, ',': tComma, '=': tAssign}
+		if k, ok := single[r]; ok {
+			toks = append(toks, token{kind: k, text: string(r), col: start})
+			i += size
+			col++
+			continue
+		}
+		op := ""
+		for j := i + 1; j <= len(s); j++ {
+			if _, ok := operators[s[i:j]]; ok {
+				op = s[i:j]
+			}
+			if !isOpRune(rune(s[j-1])) {
+				break
+			}
+		}
+		if op == "" {
+			return nil, errorf(start, "unexpected %q", r)
+		}
+		toks = append(toks, token{kind: tOp, text: op, col: start})
+		i += len(op)
+		col += len(op)
+	}
+	return append(toks, token{kind: tEOF, col: col}), nil
+}
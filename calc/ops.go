@@ -0,0 +1,120 @@
+// Package calc is a little calculator built on the functionValues lesson.
+// every operator and function is just a func(float64, float64) float64 in a
+// map, the same type compute takes, so math.Pow and math.Hypot drop straight
+// in. expressions are parsed with a Pratt parser, which handles precedence by
+// giving each operator a number saying how tightly it binds
+package calc
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Operator is a binary operator like + or ^
+type Operator struct {
+	Symbol string
+
+	// Prec is how tightly it binds. higher goes first, so * is above +
+	Prec int
+
+	// Right means a^b^c is a^(b^c). everything else groups from the left,
+	// a-b-c is (a-b)-c
+	Right bool
+
+	Fn func(float64, float64) float64
+}
+
+var operators = map[string]Operator{}
+
+// unaryPrec is how tightly a leading minus binds. above * so -2*3 is (-2)*3,
+// but not above ^ so -2^2 is -(2^2) like on paper
+const unaryPrec = 3
+
+func init() {
+	for _, op := range []Operator{
+		{Symbol: "+", Prec: 1, Fn: func(a, b float64) float64 { return a + b }},
+		{Symbol: "-", Prec: 1, Fn: func(a, b float64) float64 { return a - b }},
+		{Symbol: "*", Prec: 2, Fn: func(a, b float64) float64 { return a * b }},
+		{Symbol: "/", Prec: 2, Fn: func(a, b float64) float64 { return a / b }},
+		{Symbol: "%", Prec: 2, Fn: math.Mod},
+		{Symbol: "^", Prec: 3, Right: true, Fn: math.Pow},
+	} {
+		RegisterOperator(op)
+	}
+	for name, fn := range map[string]func(float64, float64) float64{
+		"hypot": math.Hypot,
+		"pow":   math.Pow,
+		"atan2": math.Atan2,
+		"min":   math.Min,
+		"max":   math.Max,
+		"mod":   math.Mod,
+	} {
+		RegisterFunc(name, fn)
+	}
+	for name, fn := range map[string]func(float64) float64{
+		"sqrt": math.Sqrt,
+		"abs":  math.Abs,
+		"sin":  math.Sin,
+		"cos":  math.Cos,
+		"tan":  math.Tan,
+		"ln":   math.Log,
+		"log":  math.Log10,
+		"exp":  math.Exp,
+	} {
+		RegisterFunc1(name, fn)
+	}
+}
+
+// RegisterOperator adds a binary operator. symbols are made of punctuation,
+// it panics on anything else or a symbol that's already taken
+func RegisterOperator(op Operator) {
+	if op.Symbol == "" || op.Fn == nil || op.Prec < 1 {
+		panic(fmt.Sprintf("calc: operator %q needs a symbol, a func and a precedence", op.Symbol))
+	}
+	if strings.ContainsFunc(op.Symbol, func(r rune) bool { return !isOpRune(r) }) {
+		panic(fmt.Sprintf("calc: operator %q can only use punctuation", op.Symbol))
+	}
+	if _, dup := operators[op.Symbol]; dup {
+		panic(fmt.Sprintf("calc: operator %q registered twice", op.Symbol))
+	}
+	operators[op.Symbol] = op
+}
+
+// a function takes one or two arguments
+type function struct {
+	fn1 func(float64) float64
+	fn2 func(float64, float64) float64
+}
+
+func (f function) arity() int {
+	if f.fn1 != nil {
+		return 1
+	}
+	return 2
+}
+
+var funcs = map[string]function{}
+
+// RegisterFunc adds a two argument function, called as name(a, b)
+func RegisterFunc(name string, fn func(float64, float64) float64) {
+	registerFunc(name, function{fn2: fn})
+}
+
+// RegisterFunc1 adds a one argument function, called as name(x)
+func RegisterFunc1(name string, fn func(float64) float64) {
+	registerFunc(name, function{fn1: fn})
+}
+
+func registerFunc(name string, f function) {
+	if _, dup := funcs[name]; dup {
+		panic(fmt.Sprintf("calc: function %q registered twice", name))
+	}
+	funcs[name] = f
+}
+
+// isOpRune says what operators can be made of. brackets, commas and = are
+// taken by the parser
+func isOpRune(r rune) bool {
+	return strings.ContainsRune("+-*/%^!&|<>~@#$?:", r)
+}
@@ -0,0 +1,235 @@
+package calc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is a parsed expression
+type Node interface {
+	// Eval works the expression out, looking variables up in vars
+	Eval(vars map[string]float64) (float64, error)
+
+	// String prints it back with every bracket spelled out, (1 + (2 * 3)),
+	// which shows how it was grouped
+	String() string
+}
+
+type (
+	num struct {
+		v float64
+	}
+	variable struct {
+		name string
+		col  int
+	}
+	neg struct {
+		x Node
+	}
+	binary struct {
+		op   Operator
+		l, r Node
+	}
+	call struct {
+		name string
+		col  int
+		fn   function
+		args []Node
+	}
+)
+
+func (n num) Eval(map[string]float64) (float64, error) { return n.v, nil }
+func (n num) String() string                           { return strconv.FormatFloat(n.v, 'g', -1, 64) }
+
+func (v variable) Eval(vars map[string]float64) (float64, error) {
+	x, ok := vars[v.name]
+	if !ok {
+		return 0, errorf(v.col, "%s isn't set", v.name)
+	}
+	return x, nil
+}
+func (v variable) String() string { return v.name }
+
+func (n neg) Eval(vars map[string]float64) (float64, error) {
+	x, err := n.x.Eval(vars)
+	return -x, err
+}
+func (n neg) String() string { return "(-" + n.x.String() + ")" }
+
+func (b binary) Eval(vars map[string]float64) (float64, error) {
+	l, err := b.l.Eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.r.Eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	return b.op.Fn(l, r), nil
+}
+func (b binary) String() string {
+	return fmt.Sprintf("(%s %s %s)", b.l, b.op.Symbol, b.r)
+}
+
+func (c call) Eval(vars map[string]float64) (float64, error) {
+	args := make([]float64, len(c.args))
+	for i, a := range c.args {
+		var err error
+		if args[i], err = a.Eval(vars); err != nil {
+			return 0, err
+		}
+	}
+	if c.fn.fn1 != nil {
+		return c.fn.fn1(args[0]), nil
+	}
+	return c.fn.fn2(args[0], args[1]), nil
+}
+func (c call) String() string {
+	args := make([]string, len(c.args))
+	for i, a := range c.args {
+		args[i] = a.String()
+	}
+	return c.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(k kind, what string) (token, error) {
+	t := p.next()
+	if t.kind != k {
+		return t, errorf(t.col, "expected %s, found %s", what, describe(t))
+	}
+	return t, nil
+}
+
+func describe(t token) string {
+	if t.kind == tEOF {
+		return "the end"
+	}
+	return strconv.Quote(t.text)
+}
+
+// Parse parses a whole expression
+func Parse(s string) (Node, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	n, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tEOF {
+		return nil, errorf(t.col, "unexpected %s", describe(t))
+	}
+	return n, nil
+}
+
+// expr is the heart of the Pratt parser. it reads one operand, then keeps
+// taking operators as long as they bind tighter than minPrec. each operator's
+// right hand side is parsed the same way with the bar raised to the
+// operator's own precedence, so a*b stays together in a+a*b but a+b doesn't in
+// a*a+b
+func (p *parser) expr(minPrec int) (Node, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tOp {
+			return left, nil
+		}
+		op := operators[t.text]
+		if op.Prec <= minPrec {
+			return left, nil
+		}
+		p.next()
+		// a right associative operator lets the same precedence carry on
+		// into its right hand side, so a^b^c becomes a^(b^c)
+		next := op.Prec
+		if op.Right {
+			next--
+		}
+		right, err := p.expr(next)
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, l: left, r: right}
+	}
+}
+
+func (p *parser) operand() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tNum:
+		return num{t.num}, nil
+	case tOp:
+		if t.text == "-" {
+			x, err := p.expr(unaryPrec - 1)
+			if err != nil {
+				return nil, err
+			}
+			return neg{x}, nil
+		}
+		if t.text == "+" {
+			return p.expr(unaryPrec - 1)
+		}
+	case tLParen:
+		x, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tRParen, ")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	case tIdent:
+		if p.peek().kind == tLParen {
+			return p.call(t)
+		}
+		return variable{name: t.text, col: t.col}, nil
+	}
+	return nil, errorf(t.col, "expected a number, found %s", describe(t))
+}
+
+func (p *parser) call(name token) (Node, error) {
+	fn, ok := funcs[name.text]
+	if !ok {
+		return nil, errorf(name.col, "no function called %s", name.text)
+	}
+	p.next() // (
+	c := call{name: name.text, col: name.col, fn: fn}
+	for p.peek().kind != tRParen {
+		if len(c.args) > 0 {
+			if _, err := p.expect(tComma, ", or )"); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		c.args = append(c.args, arg)
+	}
+	p.next() // )
+	if len(c.args) != fn.arity() {
+		return nil, errorf(name.col, "%s takes %d arguments, not %d", name.text, fn.arity(), len(c.args))
+	}
+	return c, nil
+}
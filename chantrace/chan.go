@@ -0,0 +1,186 @@
+package chantrace
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// Chan is a chan T that tells a Tracer about everything done with it.
+// when neither side has to wait the send is always recorded before the
+// receive. when one side was blocked, the side that didn't block gets its
+// event in first, so a receive can show up just ahead of its send
+type Chan[T any] struct {
+	tr   *Tracer
+	name string
+	c    chan T
+}
+
+// NewChan makes a traced channel. size is the buffer size like in make
+func NewChan[T any](tr *Tracer, name string, size int) *Chan[T] {
+	return &Chan[T]{tr: tr, name: name, c: make(chan T, size)}
+}
+
+// Name returns the name the channel shows up as
+func (c *Chan[T]) Name() string { return c.name }
+
+// Send is c <- v
+func (c *Chan[T]) Send(v T) {
+	// try without blocking first, holding the tracer lock so the send is
+	// recorded before the receiver can record its side
+	c.tr.mu.Lock()
+	select {
+	case c.c <- v:
+		c.tr.add(Event{Kind: Send, Chan: c.name, Text: fmt.Sprint(v)})
+		c.tr.mu.Unlock()
+		return
+	default:
+	}
+	c.tr.mu.Unlock()
+
+	c.tr.blocked("sending to "+c.name, func() { c.c <- v })
+	c.tr.record(Event{Kind: Send, Chan: c.name, Text: fmt.Sprint(v)})
+}
+
+// Recv is v, ok := <-c
+func (c *Chan[T]) Recv() (v T, ok bool) {
+	c.tr.mu.Lock()
+	select {
+	case v, ok = <-c.c:
+		c.tr.add(c.recvEvent(v, ok))
+		c.tr.mu.Unlock()
+		return v, ok
+	default:
+	}
+	c.tr.mu.Unlock()
+
+	c.tr.blocked("receiving from "+c.name, func() { v, ok = <-c.c })
+	c.tr.record(c.recvEvent(v, ok))
+	return v, ok
+}
+
+func (c *Chan[T]) recvEvent(v T, ok bool) Event {
+	text := fmt.Sprint(v)
+	if !ok {
+		text = "closed"
+	}
+	return Event{Kind: Recv, Chan: c.name, Text: text}
+}
+
+// All receives until the channel is closed, like for v := range c
+func (c *Chan[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			v, ok := c.Recv()
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Close is close(c)
+func (c *Chan[T]) Close() {
+	c.tr.record(Event{Kind: Close, Chan: c.name})
+	close(c.c)
+}
+
+// Case is one case of a traced select
+type Case struct {
+	rc    reflect.SelectCase
+	label string
+	ch    string
+}
+
+// SendCase is case c <- v
+func (c *Chan[T]) SendCase(v T) Case {
+	return Case{
+		rc:    reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(c.c), Send: reflect.ValueOf(&v).Elem()},
+		label: fmt.Sprintf("%s <- %v", c.name, v),
+		ch:    c.name,
+	}
+}
+
+// RecvCase is case <-c
+func (c *Chan[T]) RecvCase() Case {
+	return Case{
+		rc:    reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c.c)},
+		label: "<-" + c.name,
+		ch:    c.name,
+	}
+}
+
+// DoneCase is case <-ctx.Done(). pair it with a ctx from Tracer.WithCancel
+// so the cancel shows up too
+func DoneCase(ctx context.Context) Case {
+	return Case{
+		rc:    reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		label: "<-ctx.Done()",
+		ch:    "ctx.Done()",
+	}
+}
+
+// Default is the default case
+func Default() Case {
+	return Case{rc: reflect.SelectCase{Dir: reflect.SelectDefault}, label: "default"}
+}
+
+// Select runs a select over cases and returns the index of the case that ran.
+// for a receive it also returns the value and whether the channel was open,
+// same as reflect.Select
+func (t *Tracer) Select(cases ...Case) (chosen int, recv any, recvOK bool) {
+	rcs := make([]reflect.SelectCase, len(cases))
+	hasDefault := false
+	for i, c := range cases {
+		rcs[i] = c.rc
+		hasDefault = hasDefault || c.rc.Dir == reflect.SelectDefault
+	}
+
+	if hasDefault {
+		t.mu.Lock()
+		chosen, rv, ok := reflect.Select(rcs)
+		t.addChoice(cases[chosen], rv, ok)
+		t.mu.Unlock()
+		return chosen, value(rv, ok), ok
+	}
+
+	// same as Send and Recv. try it with a default first to see if it blocks
+	t.mu.Lock()
+	chosen, rv, ok := reflect.Select(append(rcs, reflect.SelectCase{Dir: reflect.SelectDefault}))
+	if chosen < len(cases) {
+		t.addChoice(cases[chosen], rv, ok)
+		t.mu.Unlock()
+		return chosen, value(rv, ok), ok
+	}
+	t.mu.Unlock()
+
+	t.blocked("in select", func() { chosen, rv, ok = reflect.Select(rcs) })
+	t.mu.Lock()
+	t.addChoice(cases[chosen], rv, ok)
+	t.mu.Unlock()
+	return chosen, value(rv, ok), ok
+}
+
+// addChoice records the select event and the send or receive it did.
+// needs t.mu held
+func (t *Tracer) addChoice(c Case, rv reflect.Value, ok bool) {
+	t.add(Event{Kind: Select, Text: c.label})
+	switch c.rc.Dir {
+	case reflect.SelectSend:
+		t.add(Event{Kind: Send, Chan: c.ch, Text: fmt.Sprint(c.rc.Send.Interface())})
+	case reflect.SelectRecv:
+		text := "closed"
+		if ok {
+			text = fmt.Sprint(rv.Interface())
+		}
+		t.add(Event{Kind: Recv, Chan: c.ch, Text: text})
+	}
+}
+
+func value(rv reflect.Value, ok bool) any {
+	if !ok || !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
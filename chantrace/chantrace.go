@@ -0,0 +1,186 @@
+// Package chantrace shows what goroutines do with channels. wrap channels in
+// a Chan, do the sends, receives and selects through it, then render the
+// Tracer's events as a timeline, a mermaid sequence diagram or an svg.
+//
+// every event is also logged to runtime/trace and blocking operations are
+// regions, so running with tour trace --runtime-trace out.trace lets you
+// open the same run in go tool trace
+package chantrace
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/trace"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind is what happened
+type Kind int
+
+const (
+	Spawn   Kind = iota // a goroutine started another one
+	Send                // a value went into a channel
+	Recv                // a value came out of a channel, or it was closed
+	Block               // an operation couldn't go ahead straight away
+	Unblock             // and now it can
+	Close               // a channel was closed
+	Select              // a select picked a case
+	Log                 // anything else, usually what the lesson printed
+)
+
+var kindNames = [...]string{"go", "send", "recv", "block", "unblock", "close", "select", "log"}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Event is one thing a goroutine did
+type Event struct {
+	Seq  int
+	At   time.Duration // since the tracer was made
+	G    int64         // goroutine id, the same number a panic prints
+	Kind Kind
+	Chan string // channel name, if there is one
+	Text string // value sent or received, select choice, log message...
+
+	Child int64 // the new goroutine for Spawn events
+}
+
+func (e Event) String() string {
+	switch e.Kind {
+	case Spawn:
+		return fmt.Sprintf("go G%d", e.Child)
+	case Send:
+		return fmt.Sprintf("%s <- %s", e.Chan, e.Text)
+	case Recv:
+		return fmt.Sprintf("<-%s = %s", e.Chan, e.Text)
+	case Block, Unblock:
+		return fmt.Sprintf("%s %s", e.Kind, e.Text)
+	case Close:
+		return "close(" + e.Chan + ")"
+	case Select:
+		return "select: " + e.Text
+	}
+	return e.Text
+}
+
+// Tracer collects events. it is safe to use from many goroutines
+type Tracer struct {
+	ctx   context.Context
+	task  *trace.Task
+	start time.Time
+
+	wg sync.WaitGroup // goroutines started with Go
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// New returns a tracer. its events go into a runtime/trace task under ctx
+func New(ctx context.Context) *Tracer {
+	ctx, task := trace.NewTask(ctx, "chantrace")
+	return &Tracer{ctx: ctx, task: task, start: time.Now()}
+}
+
+// how long Stop gives goroutines to finish
+const stopGrace = time.Second
+
+// Stop waits a little for goroutines started with Go to finish, since lessons
+// often return before their goroutines have recorded their last events. then
+// it ends the runtime/trace task. events are still readable afterwards
+func (t *Tracer) Stop() {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(stopGrace): // stuck for good, eg. a deadlock demo
+	}
+	t.task.End()
+}
+
+// Events returns a copy of everything recorded so far in order
+func (t *Tracer) Events() []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Event(nil), t.events...)
+}
+
+func (t *Tracer) record(e Event) {
+	t.mu.Lock()
+	t.add(e)
+	t.mu.Unlock()
+}
+
+// add needs t.mu held
+func (t *Tracer) add(e Event) {
+	if e.G == 0 {
+		e.G = goid()
+	}
+	e.Seq = len(t.events)
+	e.At = time.Since(t.start)
+	t.events = append(t.events, e)
+	trace.Log(t.ctx, e.Kind.String(), fmt.Sprintf("G%d %s", e.G, e))
+}
+
+// Go starts f on a new goroutine and records who started it. it waits for
+// the new goroutine to say its id so the spawn is recorded before anything
+// the new goroutine does
+func (t *Tracer) Go(f func()) {
+	id, recorded := make(chan int64), make(chan struct{})
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		id <- goid()
+		<-recorded
+		f()
+	}()
+	t.record(Event{Kind: Spawn, Child: <-id})
+	close(recorded)
+}
+
+// WithCancel is context.WithCancel, except calling cancel is recorded as
+// closing ctx.Done(), which is what it does
+func (t *Tracer) WithCancel(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() { t.record(Event{Kind: Close, Chan: "ctx.Done()"}) })
+		cancel()
+	}
+}
+
+// Printf records a log event, eg. what the lesson would have printed
+func (t *Tracer) Printf(format string, args ...any) {
+	t.record(Event{Kind: Log, Text: fmt.Sprintf(format, args...)})
+}
+
+// blocked records a Block, runs wait inside a runtime/trace region, then
+// records the Unblock
+func (t *Tracer) blocked(what string, wait func()) {
+	t.record(Event{Kind: Block, Text: what})
+	trace.WithRegion(t.ctx, what, wait)
+	t.record(Event{Kind: Unblock, Text: what})
+}
+
+// goid digs the current goroutine's id out of a stack trace. the runtime
+// deliberately doesn't hand it out, this is only ok for debugging tools
+func goid() int64 {
+	var buf [64]byte
+	s := string(buf[:runtime.Stack(buf[:], false)])
+	s = strings.TrimPrefix(s, "goroutine ")
+	if i := strings.IndexByte(s, ' '); i > 0 {
+		s = s[:i]
+	}
+	id, _ := strconv.ParseInt(s, 10, 64)
+	return id
+}
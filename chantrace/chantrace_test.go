@@ -0,0 +1,210 @@
+package chantrace
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// strs is what goroutine g did, in order. g 0 means everyone
+func strs(events []Event, g int64) []string {
+	var s []string
+	for _, e := range events {
+		if g == 0 || e.G == g {
+			s = append(s, e.String())
+		}
+	}
+	return s
+}
+
+// waitFor polls until an event of kind k has been recorded, eg. a Block
+// once another goroutine is stuck. it's called off the test goroutine too,
+// so it can't use t.Fatal
+func waitFor(t *testing.T, tr *Tracer, k Kind) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, e := range tr.Events() {
+			if e.Kind == k {
+				return
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Errorf("no %s event", k)
+}
+
+func TestChanSendRecvClose(t *testing.T) {
+	tr := New(context.Background())
+	c := NewChan[int](tr, "c", 2)
+	c.Send(1)
+	c.Send(2)
+	v, ok := c.Recv()
+	if v != 1 || !ok {
+		t.Errorf("Recv = %d, %v, want 1, true", v, ok)
+	}
+	c.Close()
+	var rest []int
+	for v := range c.All() {
+		rest = append(rest, v)
+	}
+	if !slices.Equal(rest, []int{2}) {
+		t.Errorf("All = %v, want [2]", rest)
+	}
+	if v, ok := c.Recv(); v != 0 || ok {
+		t.Errorf("Recv after close = %d, %v, want 0, false", v, ok)
+	}
+	tr.Stop()
+
+	want := []string{"c <- 1", "c <- 2", "<-c = 1", "close(c)", "<-c = 2", "<-c = closed", "<-c = closed"}
+	if got := strs(tr.Events(), 0); !slices.Equal(got, want) {
+		t.Errorf("events = %q\nwant %q", got, want)
+	}
+	for i, e := range tr.Events() {
+		if e.Seq != i {
+			t.Errorf("event %d has Seq %d", i, e.Seq)
+		}
+	}
+}
+
+func TestChanBlocked(t *testing.T) {
+	tr := New(context.Background())
+	c := NewChan[string](tr, "c", 0)
+	var recvG int64
+	done := make(chan struct{})
+	tr.Go(func() {
+		recvG = goid()
+		c.Recv()
+		close(done)
+	})
+	// the receiver is parked, so this send goes straight through and gets
+	// recorded before the receive it woke up
+	waitFor(t, tr, Block)
+	c.Send("hi")
+	<-done
+	tr.Stop()
+
+	events := tr.Events()
+	if got, want := strs(events, recvG), []string{"block receiving from c", "unblock receiving from c", "<-c = hi"}; !slices.Equal(got, want) {
+		t.Errorf("receiver did %q, want %q", got, want)
+	}
+	send := slices.IndexFunc(events, func(e Event) bool { return e.Kind == Send })
+	recv := slices.IndexFunc(events, func(e Event) bool { return e.Kind == Recv })
+	if send < 0 || recv < 0 || send > recv {
+		t.Errorf("send at %d, recv at %d in %q", send, recv, strs(events, 0))
+	}
+}
+
+func TestSelectDefault(t *testing.T) {
+	tr := New(context.Background())
+	c := NewChan[int](tr, "c", 1)
+
+	chosen, v, ok := tr.Select(c.RecvCase(), Default())
+	if chosen != 1 || v != nil || ok {
+		t.Errorf("empty: Select = %d, %v, %v, want the default", chosen, v, ok)
+	}
+	c.Send(5)
+	chosen, v, ok = tr.Select(c.RecvCase(), Default())
+	if chosen != 0 || v != 5 || !ok {
+		t.Errorf("full: Select = %d, %v, %v, want 0, 5, true", chosen, v, ok)
+	}
+	chosen, _, _ = tr.Select(c.SendCase(6), Default())
+	if chosen != 0 {
+		t.Errorf("send: Select = %d, want 0", chosen)
+	}
+	chosen, _, _ = tr.Select(c.SendCase(7), Default())
+	if chosen != 1 {
+		t.Errorf("send to full: Select = %d, want the default", chosen)
+	}
+	tr.Stop()
+
+	// a select with a default never blocks
+	want := []string{"select: default", "c <- 5", "select: <-c", "<-c = 5", "select: c <- 6", "c <- 6", "select: default"}
+	if got := strs(tr.Events(), 0); !slices.Equal(got, want) {
+		t.Errorf("events = %q\nwant %q", got, want)
+	}
+}
+
+func TestSelectBlocking(t *testing.T) {
+	tr := New(context.Background())
+	c := NewChan[int](tr, "c", 0)
+	quit := NewChan[int](tr, "quit", 0)
+	main := goid()
+	var sender int64
+	tr.Go(func() {
+		sender = goid()
+		waitFor(t, tr, Block)
+		c.Send(7)
+	})
+
+	chosen, v, ok := tr.Select(c.RecvCase(), quit.RecvCase())
+	if chosen != 0 || v != 7 || !ok {
+		t.Errorf("Select = %d, %v, %v, want 0, 7, true", chosen, v, ok)
+	}
+
+	// closing quit makes its case ready straight away
+	quit.Close()
+	chosen, v, ok = tr.Select(c.RecvCase(), quit.RecvCase())
+	if chosen != 1 || v != nil || ok {
+		t.Errorf("closed: Select = %d, %v, %v, want 1, nil, false", chosen, v, ok)
+	}
+	tr.Stop()
+
+	want := []string{fmt.Sprintf("go G%d", sender), "block in select", "unblock in select", "select: <-c", "<-c = 7", "close(quit)", "select: <-quit", "<-quit = closed"}
+	if got := strs(tr.Events(), main); !slices.Equal(got, want) {
+		t.Errorf("main did %q\nwant %q", got, want)
+	}
+}
+
+func TestGo(t *testing.T) {
+	tr := New(context.Background())
+	main := goid()
+	var child int64
+	tr.Go(func() {
+		child = goid()
+		tr.Printf("in %s", "child")
+		time.Sleep(10 * time.Millisecond)
+		tr.Printf("done")
+	})
+	// Stop waits for the goroutine, so its last event is in too
+	tr.Stop()
+
+	events := tr.Events()
+	if len(events) != 3 {
+		t.Fatalf("events = %q", strs(events, 0))
+	}
+	spawn := events[0]
+	if spawn.Kind != Spawn || spawn.G != main || spawn.Child != child || child == main {
+		t.Errorf("spawn = %+v, main G%d, child G%d", spawn, main, child)
+	}
+	if got := strs(events, child); !slices.Equal(got, []string{"in child", "done"}) {
+		t.Errorf("child did %q", got)
+	}
+	if gs := goroutines(events); !slices.Equal(gs, []int64{main, child}) {
+		t.Errorf("goroutines = %v, want [%d %d]", gs, main, child)
+	}
+}
+
+func TestWithCancel(t *testing.T) {
+	tr := New(context.Background())
+	ctx, cancel := tr.WithCancel(context.Background())
+	chosen, _, _ := tr.Select(DoneCase(ctx), Default())
+	if chosen != 1 {
+		t.Errorf("before cancel: Select = %d, want the default", chosen)
+	}
+	cancel()
+	cancel()
+	chosen, _, ok := tr.Select(DoneCase(ctx))
+	if chosen != 0 || ok {
+		t.Errorf("after cancel: Select = %d, %v, want 0, false", chosen, ok)
+	}
+	tr.Stop()
+
+	// cancelling twice only closes once
+	want := []string{"select: default", "close(ctx.Done())", "select: <-ctx.Done()", "<-ctx.Done() = closed"}
+	if got := strs(tr.Events(), 0); !slices.Equal(got, want) {
+		t.Errorf("events = %q\nwant %q", got, want)
+	}
+}
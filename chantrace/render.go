@@ -0,0 +1,152 @@
+package chantrace
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// goroutines returns the goroutine ids in the order they first show up
+func goroutines(events []Event) []int64 {
+	var gs []int64
+	seen := map[int64]bool{}
+	for _, e := range events {
+		for _, g := range []int64{e.G, e.Child} {
+			if g != 0 && !seen[g] {
+				seen[g] = true
+				gs = append(gs, g)
+			}
+		}
+	}
+	return gs
+}
+
+// WriteTimeline prints one row per event with a column per goroutine, so you
+// can read down a column to see what that goroutine was up to
+//
+//	time     G1            G7
+//	0.01ms   go G7
+//	0.02ms   block sending to c
+//	0.03ms                 <-c = 7
+func WriteTimeline(w io.Writer, events []Event) error {
+	gs := goroutines(events)
+	col := map[int64]int{}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprint(tw, "time")
+	for i, g := range gs {
+		col[g] = i
+		fmt.Fprintf(tw, "\tG%d", g)
+	}
+	fmt.Fprintln(tw)
+	for _, e := range events {
+		fmt.Fprintf(tw, "%.2fms", float64(e.At)/float64(time.Millisecond))
+		// a newline or tab in a logged message would break the table
+		fmt.Fprint(tw, strings.Repeat("\t", col[e.G]+1), cellText.Replace(e.String()))
+		// tabwriter only lines up cells that every row has, so pad out
+		fmt.Fprintln(tw, strings.Repeat("\t", len(gs)-col[e.G]-1))
+	}
+	return tw.Flush()
+}
+
+var cellText = strings.NewReplacer("\n", " ", "\t", " ")
+
+// WriteMermaid writes a mermaid sequence diagram. paste it into anything that
+// renders mermaid, eg. a github markdown file inside a ```mermaid block
+func WriteMermaid(w io.Writer, events []Event) error {
+	var b strings.Builder
+	b.WriteString("sequenceDiagram\n")
+	for _, g := range goroutines(events) {
+		fmt.Fprintf(&b, "    participant G%d\n", g)
+	}
+	seen := map[string]bool{}
+	for _, e := range events {
+		if e.Chan != "" && !seen[e.Chan] {
+			seen[e.Chan] = true
+			fmt.Fprintf(&b, "    participant %s as chan %s\n", mermaidID(e.Chan), e.Chan)
+		}
+	}
+	for _, e := range events {
+		g := fmt.Sprintf("G%d", e.G)
+		switch e.Kind {
+		case Spawn:
+			fmt.Fprintf(&b, "    %s-)G%d: go\n", g, e.Child)
+		case Send:
+			fmt.Fprintf(&b, "    %s->>%s: send %s\n", g, mermaidID(e.Chan), mermaidText(e.Text))
+		case Recv:
+			fmt.Fprintf(&b, "    %s-->>%s: recv %s\n", mermaidID(e.Chan), g, mermaidText(e.Text))
+		case Close:
+			fmt.Fprintf(&b, "    %s-x%s: close\n", g, mermaidID(e.Chan))
+		case Log:
+			fmt.Fprintf(&b, "    Note right of %s: %s\n", g, mermaidText(e.Text))
+		default:
+			fmt.Fprintf(&b, "    Note over %s: %s\n", g, mermaidText(e.String()))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// channel names become mermaid ids, which can't have spaces and the like
+func mermaidID(name string) string {
+	return "ch_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// mermaid uses ; and # for its own purposes
+func mermaidText(s string) string {
+	return strings.NewReplacer(";", "#59;", "#", "#35;", "\n", " ").Replace(s)
+}
+
+// colours for each kind of event in the svg
+var svgColors = map[Kind]string{
+	Spawn:   "#6a1b9a",
+	Send:    "#1565c0",
+	Recv:    "#2e7d32",
+	Block:   "#c62828",
+	Unblock: "#ef6c00",
+	Close:   "#455a64",
+	Select:  "#00838f",
+	Log:     "#757575",
+}
+
+// WriteSVG draws the timeline as an svg. goroutines are vertical lanes and
+// time runs down the page, one row per event
+func WriteSVG(w io.Writer, events []Event) error {
+	const (
+		laneWidth = 220
+		rowHeight = 22
+		top       = 40
+		left      = 80
+	)
+	gs := goroutines(events)
+	lane := map[int64]int{}
+	width := left + laneWidth*max(len(gs), 1)
+	height := top + rowHeight*(len(events)+1)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="monospace" font-size="12">`+"\n", width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	for i, g := range gs {
+		lane[g] = i
+		x := left + laneWidth*i + 10
+		fmt.Fprintf(&b, `<text x="%d" y="20" font-weight="bold">G%d</text>`+"\n", x, g)
+		fmt.Fprintf(&b, `<line x1="%d" y1="28" x2="%d" y2="%d" stroke="#ccc"/>`+"\n", x, x, height-10)
+	}
+	for i, e := range events {
+		x := left + laneWidth*lane[e.G] + 10
+		y := top + rowHeight*i
+		fmt.Fprintf(&b, `<text x="4" y="%d" fill="#999">%.2fms</text>`+"\n", y+4, float64(e.At)/float64(time.Millisecond))
+		fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="4" fill="%s"/>`+"\n", x, y, svgColors[e.Kind])
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s">%s</text>`+"\n", x+8, y+4, svgColors[e.Kind], html.EscapeString(e.String()))
+	}
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,143 @@
+package chantrace
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// a made up run with a fixed clock. the channel name has a space and the
+// values have the characters each format has to escape
+var sample = []Event{
+	{At: 10 * time.Microsecond, G: 1, Kind: Spawn, Child: 7},
+	{At: 20 * time.Microsecond, G: 1, Kind: Block, Text: "sending to my chan"},
+	{At: 30 * time.Microsecond, G: 7, Kind: Recv, Chan: "my chan", Text: "a;b#c"},
+	{At: 40 * time.Microsecond, G: 1, Kind: Unblock, Text: "sending to my chan"},
+	{At: 50 * time.Microsecond, G: 1, Kind: Send, Chan: "my chan", Text: "a;b#c"},
+	{At: 60 * time.Microsecond, G: 7, Kind: Log, Text: "<b>&\"x\"\nnext"},
+	{At: 1500 * time.Microsecond, G: 1, Kind: Close, Chan: "my chan"},
+}
+
+func render(t *testing.T, write func(io.Writer, []Event) error) string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := write(&b, sample); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+// tabwriter pads every cell, so ignore spaces at the ends of lines
+func trimLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestWriteTimeline(t *testing.T) {
+	got := render(t, WriteTimeline)
+	want := `time    G1                          G7
+0.01ms  go G7
+0.02ms  block sending to my chan
+0.03ms                              <-my chan = a;b#c
+0.04ms  unblock sending to my chan
+0.05ms  my chan <- a;b#c
+0.06ms                              <b>&"x" next
+1.50ms  close(my chan)
+`
+	if trimLines(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	got := render(t, WriteMermaid)
+	want := `sequenceDiagram
+    participant G1
+    participant G7
+    participant ch_my_chan as chan my chan
+    G1-)G7: go
+    Note over G1: block sending to my chan
+    ch_my_chan-->>G7: recv a#59;b#35;c
+    Note over G1: unblock sending to my chan
+    G1->>ch_my_chan: send a#59;b#35;c
+    Note right of G7: <b>&"x" next
+    G1-xch_my_chan: close
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMermaidEscaping(t *testing.T) {
+	for _, tt := range []struct{ in, id, text string }{
+		{"c", "ch_c", "c"},
+		{"my chan", "ch_my_chan", "my chan"},
+		{"c2-out", "ch_c2_out", "c2-out"},
+		{"héllo", "ch_h_llo", "héllo"},
+		{"a;b", "ch_a_b", "a#59;b"},
+		{"#1", "ch__1", "#35;1"},
+		{"two\nlines", "ch_two_lines", "two lines"},
+	} {
+		if got := mermaidID(tt.in); got != tt.id {
+			t.Errorf("mermaidID(%q) = %q, want %q", tt.in, got, tt.id)
+		}
+		if got := mermaidText(tt.in); got != tt.text {
+			t.Errorf("mermaidText(%q) = %q, want %q", tt.in, got, tt.text)
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	got := render(t, WriteSVG)
+	want := `<svg xmlns="http://www.w3.org/2000/svg" width="520" height="216" font-family="monospace" font-size="12">
+<rect width="100%" height="100%" fill="white"/>
+<text x="90" y="20" font-weight="bold">G1</text>
+<line x1="90" y1="28" x2="90" y2="206" stroke="#ccc"/>
+<text x="310" y="20" font-weight="bold">G7</text>
+<line x1="310" y1="28" x2="310" y2="206" stroke="#ccc"/>
+<text x="4" y="44" fill="#999">0.01ms</text>
+<circle cx="90" cy="40" r="4" fill="#6a1b9a"/>
+<text x="98" y="44" fill="#6a1b9a">go G7</text>
+<text x="4" y="66" fill="#999">0.02ms</text>
+<circle cx="90" cy="62" r="4" fill="#c62828"/>
+<text x="98" y="66" fill="#c62828">block sending to my chan</text>
+<text x="4" y="88" fill="#999">0.03ms</text>
+<circle cx="310" cy="84" r="4" fill="#2e7d32"/>
+<text x="318" y="88" fill="#2e7d32">&lt;-my chan = a;b#c</text>
+<text x="4" y="110" fill="#999">0.04ms</text>
+<circle cx="90" cy="106" r="4" fill="#ef6c00"/>
+<text x="98" y="110" fill="#ef6c00">unblock sending to my chan</text>
+<text x="4" y="132" fill="#999">0.05ms</text>
+<circle cx="90" cy="128" r="4" fill="#1565c0"/>
+<text x="98" y="132" fill="#1565c0">my chan &lt;- a;b#c</text>
+<text x="4" y="154" fill="#999">0.06ms</text>
+<circle cx="310" cy="150" r="4" fill="#757575"/>
+<text x="318" y="154" fill="#757575">&lt;b&gt;&amp;&#34;x&#34;
+next</text>
+<text x="4" y="176" fill="#999">1.50ms</text>
+<circle cx="90" cy="172" r="4" fill="#455a64"/>
+<text x="98" y="176" fill="#455a64">close(my chan)</text>
+</svg>
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	for name, write := range map[string]func(io.Writer, []Event) error{
+		"timeline": WriteTimeline,
+		"mermaid":  WriteMermaid,
+		"svg":      WriteSVG,
+	} {
+		var b bytes.Buffer
+		if err := write(&b, nil); err != nil || b.Len() == 0 {
+			t.Errorf("%s of no events = %q, %v", name, b.String(), err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/exercise"
+)
+
+// scratch files live under _scratch by default. the go tool ignores
+// directories starting with _ so half done solutions never break go build ./...
+const scratchDir = "_scratch"
+
+func exercisesCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("exercises", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, e := range exercise.All() {
+		fmt.Fprintf(tw, "%s\t(%s)\t%s\n", e.Name, e.Lesson, e.Description)
+	}
+	return tw.Flush()
+}
+
+// startCmd writes an exercise's stub into the scratch directory
+func startCmd(w io.Writer, args []string) error {
+	fset := flag.NewFlagSet("start", flag.ContinueOnError)
+	dir := fset.String("dir", scratchDir, "write the scratch file into `dir`")
+	force := fset.Bool("force", false, "overwrite an existing scratch file")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	e, err := lookupExercise(fset.Args())
+	if err != nil {
+		return err
+	}
+
+	p := filepath.Join(*dir, e.Name+".go")
+	if _, err := os.Stat(p); err == nil && !*force {
+		return fmt.Errorf("%s already exists. use --force to start over", p)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(p, []byte(e.Stub), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "wrote %s. fill in the TODOs then run: tour check %s\n", p, e.Name)
+	return nil
+}
+
+// checkCmd grades the learner's scratch file
+func checkCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	file := fs.String("file", "", "check this `file` instead of the one in "+scratchDir)
+	timeout := fs.Duration("timeout", time.Minute, "give up after this long")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	e, err := lookupExercise(fs.Args())
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		*file = filepath.Join(scratchDir, e.Name+".go")
+	}
+	src, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("%w. run tour start %s first", err, e.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	res, err := exercise.Check(ctx, e, src)
+	if err != nil {
+		return err
+	}
+
+	if res.BuildOutput != "" {
+		fmt.Fprintf(w, "%s doesn't compile:\n%s\n", *file, res.BuildOutput)
+		return fmt.Errorf("%s: build failed", e.Name)
+	}
+	passed := 0
+	for _, c := range res.Cases {
+		if c.Passed {
+			passed++
+			fmt.Fprintf(w, "PASS  %s\n", c.Name)
+			continue
+		}
+		fmt.Fprintf(w, "FAIL  %s\n", c.Name)
+		for _, line := range strings.Split(strings.TrimSpace(c.Output), "\n") {
+			fmt.Fprintf(w, "\t%s\n", strings.TrimSpace(line))
+		}
+	}
+	if !res.Passed() {
+		return fmt.Errorf("%s: %d of %d cases passed", e.Name, passed, len(res.Cases))
+	}
+	fmt.Fprintf(w, "all %d cases passed\n", passed)
+	return nil
+}
+
+func lookupExercise(args []string) (exercise.Exercise, error) {
+	if len(args) != 1 {
+		return exercise.Exercise{}, fmt.Errorf("give exactly one exercise. try `tour exercises`")
+	}
+	e, ok := exercise.Lookup(args[0])
+	if !ok {
+		return exercise.Exercise{}, fmt.Errorf("unknown exercise %q", args[0])
+	}
+	return e, nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/clock"
+	"github.com/DaveM7788/tourOfGo/lesson"
+
+	// these register their lessons in init. the blank identifier imports a
+	// package only for its side effects
+	_ "github.com/DaveM7788/tourOfGo/concurrency"
+	_ "github.com/DaveM7788/tourOfGo/methods"
+	_ "github.com/DaveM7788/tourOfGo/point"
+)
+
+const usage = `usage: tour <command> [arguments]
+
+commands:
+	list [--topic t]                  show the lessons
+	run <lesson>...                   run lessons by name
+	run --topic t                     run every lesson in a topic
+	run --all                         run everything
+	run --now t --seed n ...          run on a fake clock and a fixed random seed
+	run --verbose ...                 let lessons show more of what's going on
+	verify [--update] [lesson...]     check lesson output against golden files
+	trace [--format f] <lesson>       draw what a concurrency lesson's goroutines did
+	whatif [variant...]               run the broken versions of lessons safely
+	exercises                         show the exercises
+	start <exercise>                  write an exercise's stub into _scratch
+	check [--file f] <exercise>       grade your solution
+	tictactoe [--size n --ai o] ...   play tic-tac-toe against the computer
+	growth [--n n --size b]           watch append grow a slice
+	wordcount [--top n] [file...]     count the words in files or stdin
+	calc [--tree] [expression]        work something out, or start a calculator
+	pipe [--rot13 --upper ...] [file] copy a file through a chain of readers
+	pic [--out f] <image>             draw an image in the terminal or save it
+	fractal [--julia c] [--out f]     draw the Mandelbrot or a Julia set
+`
+
+// tour is the real main. it returns the exit code so it stays easy to call
+func tour(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	var err error
+	switch args[0] {
+	case "list":
+		err = listCmd(os.Stdout, args[1:])
+	case "run":
+		err = runCmd(os.Stdout, args[1:])
+	case "verify":
+		err = verifyCmd(os.Stdout, args[1:])
+	case "trace":
+		err = traceCmd(os.Stdout, args[1:])
+	case "whatif":
+		err = whatifCmd(os.Stdout, args[1:])
+	case "exercises":
+		err = exercisesCmd(os.Stdout, args[1:])
+	case "start":
+		err = startCmd(os.Stdout, args[1:])
+	case "check":
+		err = checkCmd(os.Stdout, args[1:])
+	case "tictactoe":
+		err = tictactoeCmd(os.Stdout, args[1:])
+	case "growth":
+		err = growthCmd(os.Stdout, args[1:])
+	case "wordcount":
+		err = wordcountCmd(os.Stdout, args[1:])
+	case "calc":
+		err = calcCmd(os.Stdout, args[1:])
+	case "pipe":
+		err = pipeCmd(os.Stdout, args[1:])
+	case "pic":
+		err = picCmd(os.Stdout, args[1:])
+	case "fractal":
+		err = fractalCmd(os.Stdout, args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return 0
+	default:
+		err = fmt.Errorf("unknown command %q", args[0])
+	}
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tour:", err)
+		return 1
+	}
+	return 0
+}
+
+func listCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	topic := fs.String("topic", "", "only list lessons in this `topic`")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	lessons := lesson.All()
+	if *topic != "" {
+		if lessons = lesson.ByTopic(*topic); lessons == nil {
+			return fmt.Errorf("unknown topic %q", *topic)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for i, l := range lessons {
+		if i == 0 || lessons[i-1].Topic != l.Topic {
+			fmt.Fprintf(tw, "%s\n", l.Topic)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", l.Name, l.Description)
+	}
+	return tw.Flush()
+}
+
+func runCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	topic := fs.String("topic", "", "run every lesson in this `topic`")
+	all := fs.Bool("all", false, "run every lesson")
+	now := fs.String("now", "", "pretend it is `time` (2006-01-02, RFC 3339 or a weekday like thursday) on a fake clock that sleeps instantly")
+	seed := fs.Int64("seed", 0, "seed for random numbers. random if not set")
+	verbose := fs.Bool("verbose", false, "let lessons print extra detail")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	env := lesson.RealEnv(w)
+	env.Verbose = *verbose
+	if *now != "" {
+		t, err := parseNow(*now)
+		if err != nil {
+			return err
+		}
+		env.Clock = clock.NewFake(t)
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			env.Rand = rand.New(rand.NewSource(*seed))
+		}
+	})
+
+	lessons, err := selectLessons(*all, *topic, fs.Args())
+	if err != nil {
+		return err
+	}
+	for i, l := range lessons {
+		// only bother with headers when there is more than one lesson
+		if len(lessons) > 1 {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "== %s/%s ==\n", l.Topic, l.Name)
+		}
+		l.Exec(env)
+	}
+	return nil
+}
+
+// selectLessons turns the --all, --topic and positional arguments into a list
+// of lessons. exactly one way of choosing has to be used
+func selectLessons(all bool, topic string, names []string) ([]lesson.Lesson, error) {
+	switch {
+	case all && (topic != "" || len(names) > 0), topic != "" && len(names) > 0:
+		return nil, fmt.Errorf("use only one of --all, --topic or lesson names")
+	case all:
+		return lesson.All(), nil
+	case topic != "":
+		ls := lesson.ByTopic(topic)
+		if ls == nil {
+			return nil, fmt.Errorf("unknown topic %q", topic)
+		}
+		return ls, nil
+	case len(names) == 0:
+		return nil, fmt.Errorf("no lessons given. try `tour list`")
+	}
+
+	ls := make([]lesson.Lesson, 0, len(names))
+	for _, n := range names {
+		l, ok := lesson.Lookup(n)
+		if !ok {
+			return nil, fmt.Errorf("unknown lesson %q", n)
+		}
+		ls = append(ls, l)
+	}
+	return ls, nil
+}
+
+// parseNow reads the --now flag. a bare weekday means the first one on or
+// after the playground date, so "thursday" is always the same thursday
+func parseNow(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	for d := 0; d < 7; d++ {
+		t := clock.Playground.AddDate(0, 0, d)
+		if strings.EqualFold(t.Weekday().String(), s) {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't understand --now %q. use 2006-01-02, RFC 3339 or a weekday", s)
+}
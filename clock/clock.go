@@ -0,0 +1,24 @@
+// Package clock lets lessons ask for the time without talking to the wall
+// clock directly. the real clock just calls into package time, the Fake one
+// only moves when you tell it to, so time based lessons replay instantly
+package clock
+
+import "time"
+
+// Clock is the subset of package time the lessons use
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	Tick(d time.Duration) <-chan time.Time
+}
+
+// Real is the wall clock
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Tick(d time.Duration) <-chan time.Time  { return time.Tick(d) }
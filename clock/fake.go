@@ -0,0 +1,115 @@
+package clock
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Playground is the time the go playground has always been stuck at.
+// a Tuesday, which is handy for switchStmt
+var Playground = time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+// Fake is a Clock that stands still until Advance or Sleep moves it.
+// timers from After and Tick fire as the clock passes their deadline,
+// earliest first. it is safe to share between goroutines
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	when   time.Time
+	period time.Duration // 0 for After, which fires once
+	c      chan time.Time
+}
+
+// NewFake returns a fake clock that reads now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Sleep doesn't block. it moves the clock forward by d instead, so a lesson
+// that sleeps in a loop runs instantly but still sees time pass
+func (f *Fake) Sleep(d time.Duration) {
+	f.Advance(d)
+	// let any goroutine that was woken up get a look in, like a real sleep would
+	runtime.Gosched()
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.add(d, 0)
+}
+
+// Tick behaves like time.Tick. a tick that nobody reads in time is dropped
+func (f *Fake) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return f.add(d, d)
+}
+
+func (f *Fake) add(d, period time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &fakeTimer{when: f.now.Add(d), period: period, c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- f.now
+		return t.c
+	}
+	f.timers = append(f.timers, t)
+	return t.c
+}
+
+// Advance moves the clock forward by d, firing every timer that comes due on
+// the way in deadline order
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.advanceTo(f.now.Add(d))
+}
+
+// advanceTo is Advance to a time rather than by a duration. f.mu must be held
+func (f *Fake) advanceTo(end time.Time) {
+	for {
+		sort.SliceStable(f.timers, func(i, j int) bool {
+			return f.timers[i].when.Before(f.timers[j].when)
+		})
+		if len(f.timers) == 0 || f.timers[0].when.After(end) {
+			break
+		}
+		t := f.timers[0]
+		f.now = t.when
+		select {
+		case t.c <- t.when:
+		default: // reader is behind. drop it like time.Ticker does
+		}
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+		} else {
+			f.timers = f.timers[1:]
+		}
+	}
+	f.now = end
+}
+
+// Set jumps the clock to t. timers don't fire when going backwards
+func (f *Fake) Set(t time.Time) {
+	// one lock for both, or another goroutine could move the clock between
+	// reading it and deciding which way to go
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if t.After(f.now) {
+		f.advanceTo(t)
+		return
+	}
+	f.now = t
+}
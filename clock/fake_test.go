@@ -0,0 +1,171 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeSet(t *testing.T) {
+	f := NewFake(Playground)
+	boom := f.After(time.Hour)
+
+	// backwards doesn't fire anything
+	f.Set(Playground.Add(-time.Hour))
+	if got := f.Now(); !got.Equal(Playground.Add(-time.Hour)) {
+		t.Errorf("Now = %v after setting it back an hour", got)
+	}
+	select {
+	case <-boom:
+		t.Fatal("timer fired going backwards")
+	default:
+	}
+
+	// forwards past the deadline does
+	f.Set(Playground.Add(2 * time.Hour))
+	select {
+	case when := <-boom:
+		if !when.Equal(Playground.Add(time.Hour)) {
+			t.Errorf("timer fired at %v, want its deadline", when)
+		}
+	default:
+		t.Fatal("timer didn't fire")
+	}
+	if got := f.Now(); !got.Equal(Playground.Add(2 * time.Hour)) {
+		t.Errorf("Now = %v, want 2 hours on", got)
+	}
+}
+
+// run with -race. Set used to read the time and change it under separate locks
+func TestFakeSetConcurrent(t *testing.T) {
+	f := NewFake(Playground)
+	done := make(chan bool)
+	for i := range 4 {
+		go func() {
+			for j := range 100 {
+				f.Set(Playground.Add(time.Duration(i*100+j) * time.Second))
+				f.Sleep(time.Millisecond)
+			}
+			done <- true
+		}()
+	}
+	for range 4 {
+		<-done
+	}
+}
+
+// fired reports whether c has a value waiting, and what it is
+func fired(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case when := <-c:
+		return when, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeAfterOrder(t *testing.T) {
+	f := NewFake(Playground)
+	// made out of order on purpose
+	third := f.After(3 * time.Second)
+	first := f.After(time.Second)
+	second := f.After(2 * time.Second)
+	timers := []<-chan time.Time{first, second, third}
+
+	for step := range 3 {
+		f.Advance(time.Second)
+		for i, c := range timers {
+			when, ok := fired(c)
+			if want := i == step; ok != want {
+				t.Errorf("after %ds timer %d fired = %v, want %v", step+1, i+1, ok, want)
+			}
+			if ok && !when.Equal(Playground.Add(time.Duration(i+1)*time.Second)) {
+				t.Errorf("timer %d fired at %v, want its deadline", i+1, when)
+			}
+		}
+	}
+
+	// all at once comes out the same, each at its own deadline, and the
+	// timers are used up
+	f = NewFake(Playground)
+	timers = []<-chan time.Time{f.After(3 * time.Second), f.After(time.Second), f.After(2 * time.Second)}
+	f.Advance(time.Minute)
+	for i, c := range timers {
+		when, ok := fired(c)
+		if want := Playground.Add([]time.Duration{3, 1, 2}[i] * time.Second); !ok || !when.Equal(want) {
+			t.Errorf("timer %d = %v, %v, want %v", i, when, ok, want)
+		}
+	}
+	if len(f.timers) != 0 {
+		t.Errorf("%d timers left after they all fired", len(f.timers))
+	}
+	if got := f.Now(); !got.Equal(Playground.Add(time.Minute)) {
+		t.Errorf("Now = %v, want a minute on", got)
+	}
+}
+
+func TestFakeTick(t *testing.T) {
+	f := NewFake(Playground)
+	tick := f.Tick(time.Second)
+
+	// it fires at 1s, 2s and 3s, but there's room for one tick and nobody
+	// reads until the end. 2s and 3s are dropped
+	f.Advance(3500 * time.Millisecond)
+	if when, ok := fired(tick); !ok || !when.Equal(Playground.Add(time.Second)) {
+		t.Errorf("first tick = %v, %v, want the one at 1s", when, ok)
+	}
+	if when, ok := fired(tick); ok {
+		t.Errorf("dropped tick at %v came through", when)
+	}
+	if next := f.timers[0].when; !next.Equal(Playground.Add(4 * time.Second)) {
+		t.Errorf("next tick at %v, want 4s", next)
+	}
+
+	// read each one as it comes and none are lost
+	for i := 4; i <= 6; i++ {
+		f.Advance(time.Second)
+		if when, ok := fired(tick); !ok || !when.Equal(Playground.Add(time.Duration(i)*time.Second)) {
+			t.Errorf("tick %d = %v, %v", i, when, ok)
+		}
+	}
+
+	for _, d := range []time.Duration{0, -time.Second} {
+		if c := f.Tick(d); c != nil {
+			t.Errorf("Tick(%v) = %v, want nil like time.Tick", d, c)
+		}
+	}
+}
+
+func TestFakeAfterNow(t *testing.T) {
+	f := NewFake(Playground)
+	for _, d := range []time.Duration{0, -time.Second, -time.Hour} {
+		when, ok := fired(f.After(d))
+		if !ok || !when.Equal(Playground) {
+			t.Errorf("After(%v) = %v, %v, want it ready with the current time", d, when, ok)
+		}
+	}
+	if len(f.timers) != 0 {
+		t.Errorf("After(0) left %d timers behind", len(f.timers))
+	}
+	if got := f.Now(); !got.Equal(Playground) {
+		t.Errorf("Now = %v, After moved the clock", got)
+	}
+}
+
+func TestFakeSleep(t *testing.T) {
+	f := NewFake(Playground)
+	boom := f.After(time.Minute)
+	f.Sleep(30 * time.Second)
+	if got := f.Now(); !got.Equal(Playground.Add(30 * time.Second)) {
+		t.Errorf("Now = %v after sleeping 30s", got)
+	}
+	if _, ok := fired(boom); ok {
+		t.Error("timer fired early")
+	}
+	f.Sleep(45 * time.Second)
+	if when, ok := fired(boom); !ok || !when.Equal(Playground.Add(time.Minute)) {
+		t.Errorf("timer = %v, %v after sleeping past it", when, ok)
+	}
+	if got := f.Now(); !got.Equal(Playground.Add(75 * time.Second)) {
+		t.Errorf("Now = %v, want 75s on", got)
+	}
+}
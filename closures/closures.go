@@ -0,0 +1,86 @@
+// Package closures grows adder from the functionClosures lesson into a small
+// toolbox. everything here returns a function that carries its own
+// variables around, so two values returned by the same call never share
+// state. pos and neg in the lesson each have their own sum for the same reason
+package closures
+
+import (
+	"sync"
+)
+
+// Memoize remembers what f returned for each argument so it only runs once
+// per argument. f should always give the same answer for the same input.
+// safe to call from several goroutines
+func Memoize[K comparable, V any](f func(K) V) func(K) V {
+	var mu sync.Mutex
+	cache := make(map[K]V)
+	return func(k K) V {
+		mu.Lock()
+		v, ok := cache[k]
+		mu.Unlock()
+		if ok {
+			return v
+		}
+		// not holding the lock while f runs, so a recursive f can call
+		// itself through the memoized version. two goroutines might both
+		// work out the same k, which is harmless
+		v = f(k)
+		mu.Lock()
+		cache[k] = v
+		mu.Unlock()
+		return v
+	}
+}
+
+// Once runs f the first time the returned function is called and hands back
+// that same result every time after. sync.OnceValue does this too
+func Once[T any](f func() T) func() T {
+	var once sync.Once
+	var v T
+	return func() T {
+		once.Do(func() {
+			v = f()
+			f = nil // let f and whatever it holds onto be collected
+		})
+		return v
+	}
+}
+
+// Compose returns g(f(x)). read it as f then g
+func Compose[A, B, C any](f func(A) B, g func(B) C) func(A) C {
+	return func(a A) C {
+		return g(f(a))
+	}
+}
+
+// Pipe runs x through each of fs in order. Pipe() on its own returns x
+func Pipe[T any](fs ...func(T) T) func(T) T {
+	return func(x T) T {
+		for _, f := range fs {
+			x = f(x)
+		}
+		return x
+	}
+}
+
+// Curry2 turns a two argument function into one that takes its arguments
+// one at a time. Curry2(math.Pow)(2) is a function that raises 2 to a power
+func Curry2[A, B, C any](f func(A, B) C) func(A) func(B) C {
+	return func(a A) func(B) C {
+		return func(b B) C {
+			return f(a, b)
+		}
+	}
+}
+
+// Fibonacci returns a function that returns successive fibonacci numbers,
+// 0, 1, 1, 2, 3, 5... the answer to the fibonacci exercise, so try that
+// first. $ go run . start fibonacci
+func Fibonacci() func() int {
+	a, b := 0, 1
+	return func() int {
+		f := a
+		a, b = b, a+b
+		return f
+	}
+}
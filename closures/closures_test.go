@@ -0,0 +1,196 @@
+package closures
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/clock"
+)
+
+func TestFibonacciIndependent(t *testing.T) {
+	f, g := Fibonacci(), Fibonacci()
+	want := []int{0, 1, 1, 2, 3, 5, 8, 13}
+	// run f ahead, then catch g up. g mustn't see anything f did
+	for _, w := range want[:5] {
+		if got := f(); got != w {
+			t.Fatalf("f() = %d, want %d", got, w)
+		}
+	}
+	for _, w := range want {
+		if got := g(); got != w {
+			t.Fatalf("g() = %d, want %d", got, w)
+		}
+	}
+	for _, w := range want[5:] {
+		if got := f(); got != w {
+			t.Fatalf("f() = %d, want %d after g ran", got, w)
+		}
+	}
+}
+
+func TestStatsIndependent(t *testing.T) {
+	m1, m2 := Mean(), Mean()
+	v1, v2 := Variance(), Variance()
+	mm1, mm2 := MinMax(), MinMax()
+	for _, x := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		m1(x)
+		v1(x)
+		mm1(x)
+	}
+	if got := m2(10); got != 10 {
+		t.Errorf("a fresh Mean saw the other's numbers: %g", got)
+	}
+	if got := v2(10); got != 0 {
+		t.Errorf("a fresh Variance saw the other's numbers: %g", got)
+	}
+	if lo, hi := mm2(10); lo != 10 || hi != 10 {
+		t.Errorf("a fresh MinMax saw the other's numbers: %g, %g", lo, hi)
+	}
+	// and the first ones carry on from where they were
+	if got := m1(5); got != 5 {
+		t.Errorf("mean = %g, want 5", got)
+	}
+	if got := v1(5); math.Abs(got-32.0/9) > 1e-12 {
+		t.Errorf("variance = %g, want %g", got, 32.0/9)
+	}
+	if lo, hi := mm1(5); lo != 2 || hi != 9 {
+		t.Errorf("minmax = %g, %g, want 2, 9", lo, hi)
+	}
+}
+
+func TestVarianceBigNumbers(t *testing.T) {
+	// the naive sum of squares gets this badly wrong
+	v := Variance()
+	var got float64
+	for _, x := range []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16} {
+		got = v(x)
+	}
+	if got != 22.5 {
+		t.Errorf("variance = %g, want 22.5", got)
+	}
+}
+
+func TestMemoizeIndependent(t *testing.T) {
+	calls := 0
+	f := func(n int) string { calls++; return strconv.Itoa(n) }
+	a, b := Memoize(f), Memoize(f)
+	a(1)
+	a(1)
+	a(2)
+	if calls != 2 {
+		t.Errorf("a ran f %d times for 2 arguments", calls)
+	}
+	// b has its own cache, so it has to work out 1 again
+	b(1)
+	if calls != 3 {
+		t.Errorf("b used a's cache")
+	}
+}
+
+func TestMemoizeConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[int]int{}
+	sq := Memoize(func(n int) int {
+		mu.Lock()
+		calls[n]++
+		mu.Unlock()
+		return n * n
+	})
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range 100 {
+				if got := sq(n); got != n*n {
+					t.Errorf("sq(%d) = %d", n, got)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for n := range 100 {
+		if got := sq(n); got != n*n || calls[n] == 0 {
+			t.Errorf("sq(%d) = %d after %d calls", n, got, calls[n])
+		}
+	}
+}
+
+func TestOnceIndependent(t *testing.T) {
+	n := 0
+	next := func() int { n++; return n }
+	a, b := Once(next), Once(next)
+	if a() != 1 || a() != 1 {
+		t.Error("a ran f twice")
+	}
+	if got := b(); got != 2 {
+		t.Errorf("b() = %d, want 2, it has its own once", got)
+	}
+}
+
+func TestComposePipeCurry(t *testing.T) {
+	inc := func(n int) int { return n + 1 }
+	double := func(n int) int { return n * 2 }
+	if got := Compose(inc, strconv.Itoa)(41); got != "42" {
+		t.Errorf("Compose = %q", got)
+	}
+	if got := Pipe(inc, double)(3); got != 8 {
+		t.Errorf("Pipe(inc, double)(3) = %d, want 8", got)
+	}
+	if got := Pipe[int]()(3); got != 3 {
+		t.Errorf("Pipe()(3) = %d", got)
+	}
+	pow2 := Curry2(math.Pow)(2)
+	if got := pow2(10); got != 1024 {
+		t.Errorf("2^10 = %g", got)
+	}
+}
+
+func TestThrottleIndependent(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	na, nb := 0, 0
+	a := Throttle(c, time.Second, func() { na++ })
+	b := Throttle(c, time.Second, func() { nb++ })
+	if !a() || a() {
+		t.Error("a should run once then be throttled")
+	}
+	// a running doesn't use up b's turn
+	if !b() {
+		t.Error("b was throttled by a")
+	}
+	c.Advance(time.Second)
+	if !a() {
+		t.Error("a still throttled after d")
+	}
+	if na != 2 || nb != 1 {
+		t.Errorf("a ran %d times, b %d", na, nb)
+	}
+}
+
+func TestDebounceIndependent(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	ran := make(chan string, 10)
+	a := Debounce(c, time.Second, func() { ran <- "a" })
+	b := Debounce(c, time.Second, func() { ran <- "b" })
+	a()
+	a()
+	c.Advance(500 * time.Millisecond)
+	// calling b doesn't restart a's wait
+	b()
+	c.Advance(500 * time.Millisecond)
+	if got := <-ran; got != "a" {
+		t.Errorf("%s ran first, want a", got)
+	}
+	c.Advance(500 * time.Millisecond)
+	if got := <-ran; got != "b" {
+		t.Errorf("%s ran second, want b", got)
+	}
+	select {
+	case got := <-ran:
+		t.Errorf("%s ran again, the burst of calls should give one run each", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
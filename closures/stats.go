@@ -0,0 +1,38 @@
+package closures
+
+import "math"
+
+// Mean returns a running mean. feed it one number at a time and it returns
+// the mean of everything so far, without keeping the numbers
+func Mean() func(float64) float64 {
+	n, mean := 0, 0.0
+	return func(x float64) float64 {
+		n++
+		mean += (x - mean) / float64(n)
+		return mean
+	}
+}
+
+// Variance returns a running population variance. adding up x and x*x and
+// subtracting at the end loses precision badly when the numbers are big and
+// close together, so this uses Welford's method, which updates the mean and
+// the sum of squared differences from it as it goes
+func Variance() func(float64) float64 {
+	n, mean, m2 := 0, 0.0, 0.0
+	return func(x float64) float64 {
+		n++
+		d := x - mean
+		mean += d / float64(n)
+		m2 += d * (x - mean)
+		return m2 / float64(n)
+	}
+}
+
+// MinMax returns the smallest and largest numbers seen so far
+func MinMax() func(float64) (min, max float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	return func(x float64) (float64, float64) {
+		lo, hi = math.Min(lo, x), math.Max(hi, x)
+		return lo, hi
+	}
+}
@@ -0,0 +1,57 @@
+package closures
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/clock"
+)
+
+// Debounce returns a function that calls f once things have gone quiet for
+// d. every call restarts the wait, so a burst of calls ends up as one call
+// to f, d after the last of them. f runs on its own goroutine. c is usually
+// clock.Real, a clock.Fake makes it easy to see what happens when
+func Debounce(c clock.Clock, d time.Duration, f func()) func() {
+	var mu sync.Mutex
+	gen := 0
+	return func() {
+		mu.Lock()
+		gen++
+		mine := gen
+		mu.Unlock()
+
+		// there's no Stop on the clock's timers. instead every call waits
+		// and only the one that is still the latest when it wakes runs f
+		fire := c.After(d)
+		go func() {
+			<-fire
+			mu.Lock()
+			latest := mine == gen
+			mu.Unlock()
+			if latest {
+				f()
+			}
+		}()
+	}
+}
+
+// Throttle returns a function that calls f at most once every d. calls in
+// between are dropped and it reports whether f ran. unlike Debounce f runs
+// straight away on the calling goroutine
+func Throttle(c clock.Clock, d time.Duration, f func()) func() bool {
+	var mu sync.Mutex
+	var last time.Time
+	ran := false
+	return func() bool {
+		mu.Lock()
+		now := c.Now()
+		if ran && now.Sub(last) < d {
+			mu.Unlock()
+			return false
+		}
+		ran, last = true, now
+		mu.Unlock()
+		f()
+		return true
+	}
+}
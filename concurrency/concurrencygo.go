@@ -1,37 +1,60 @@
-package main
+package concurrency
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"time"
+
+	"github.com/DaveM7788/tourOfGo/fractal"
+	"github.com/DaveM7788/tourOfGo/lesson"
+	"github.com/DaveM7788/tourOfGo/parallel"
+	"github.com/DaveM7788/tourOfGo/pipeline"
 )
 
-func main() {
-	simpleGoroutine()
-	channelsChan()
-	bufferedChan()
-	rangeAndClose()
-	selectSel()
-	defaultSelect()
+func init() {
+	for _, l := range []lesson.Lesson{
+		{Name: "simpleGoroutine", Description: "starting a goroutine with go", RunEnv: simpleGoroutine, Unordered: true},
+		{Name: "channelsChan", Description: "summing a slice on two goroutines over a channel", Run: channelsChan},
+		{Name: "parallelSum", Description: "the same sum split across any number of workers", Run: parallelSum},
+		{Name: "fractalTiles", Description: "a Mandelbrot set drawn by a pool of goroutines", Run: fractalTiles},
+		{Name: "bufferedChan", Description: "buffered channels only block when full", Run: bufferedChan},
+		{Name: "rangeAndClose", Description: "ranging over a channel until it is closed", Run: rangeAndClose},
+		{Name: "closeChan", Description: "closing a channel by hand, and v, ok := <-c", Run: closeChan},
+		{Name: "selectSel", Description: "select waits on several channel operations", Run: selectSel},
+		{Name: "defaultSelect", Description: "select with a default case", RunEnv: defaultSelect},
+	} {
+		l.Topic = "concurrency"
+		lesson.Register(l)
+	}
 }
 
-func simpleGoroutine() {
+func simpleGoroutine(env *lesson.Env) {
 	// A goroutine is a lightweight thread managed by the Go runtime. uses go keyword
-	go say("world")
-	say("hello")
+	done := make(chan bool)
+	go func() {
+		say(env, "world")
+		done <- true
+	}()
+	say(env, "hello")
+	// the program ends when main returns, without waiting for other
+	// goroutines. so without this the last world can go missing. channels
+	// are the next lesson
+	<-done
 	// go f(x, y, z)
 	// The evaluation of f, x, y, and z happens in the current goroutine and the execution
 	// of f happens in the new goroutine
 }
 
-func say(s string) {
+func say(env *lesson.Env, s string) {
 	for i := 0; i < 5; i++ {
-		time.Sleep(100 * time.Millisecond)
-		fmt.Println(s)
+		env.Clock.Sleep(100 * time.Millisecond)
+		fmt.Fprintln(env.Out, s)
 	}
 }
 
 // Channels are a typed conduit through which you can send and receive values with the channel operator, <-
-func channelsChan() {
+func channelsChan(w io.Writer) {
 	s := []int{7, 2, 8, -9, 4, 0}
 
 	// Like maps and slices, channels must be created before use. Note the chan keyword
@@ -40,7 +63,7 @@ func channelsChan() {
 	go sum(s[len(s)/2:], c)
 	x, y := <-c, <-c // receive from c
 
-	fmt.Println(x, y, x+y)
+	fmt.Fprintln(w, x, y, x+y)
 }
 
 /*
@@ -59,15 +82,73 @@ func sum(s []int, c chan int) {
 	c <- sum // send sum to c
 }
 
-func bufferedChan() {
+// parallel.Reduce does what channelsChan does for any number of goroutines and
+// any associative function, not just +
+func parallelSum(w io.Writer) {
+	s := []int{7, 2, 8, -9, 4, 0}
+	total, stats, err := parallel.Sum(context.Background(), s, 3)
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
+	for _, ws := range stats.Workers {
+		fmt.Fprintf(w, "worker %d summed %v\n", ws.Worker, s[ws.Start:ws.End])
+	}
+	fmt.Fprintln(w, total)
+
+	// max is associative too
+	biggest, _, _ := parallel.Reduce(context.Background(), s, 0, func(a, b int) int { return max(a, b) })
+	fmt.Fprintln(w, "biggest", biggest)
+}
+
+// the complex numbers from basicTypes, the image package and a worker pool
+// all at once. the picture is cut into tiles and 4 goroutines take tiles off
+// a channel until it's empty. the points in the set take far longer than the
+// rest, so whoever finishes early just takes another tile. see it properly
+// with $ go run . fractal
+func fractalTiles(w io.Writer) {
+	img, stats, err := fractal.Render(context.Background(), fractal.Config{
+		View:     fractal.DefaultView,
+		Width:    64,
+		Height:   24,
+		MaxIter:  64,
+		TileSize: 8,
+		Workers:  4,
+	})
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
+	// the gray palette makes R the brightness, brighter the longer a point
+	// took to escape, and points in the set are black. pick characters by it
+	const ramp = " .:-=+*#%"
+	for y := range img.Bounds().Dy() {
+		for x := range img.Bounds().Dx() {
+			r := int(img.RGBAAt(x, y).R)
+			if r == 0 {
+				fmt.Fprint(w, "@")
+				continue
+			}
+			fmt.Fprint(w, string(ramp[r*(len(ramp)-1)/255]))
+		}
+		fmt.Fprintln(w)
+	}
+	done := 0
+	for _, n := range stats.Workers {
+		done += n
+	}
+	fmt.Fprintln(w, len(stats.Workers), "workers drew", done, "of", stats.Tiles, "tiles")
+}
+
+func bufferedChan(w io.Writer) {
 	// Channels can be buffered. Provide the buffer length as the second argument to make to initialize a buffered channel
 	// Sends to a buffered channel block only when the buffer is full. Receives block when the buffer is empty.
 	ch := make(chan int, 2)
 	ch <- 1
 	ch <- 2
-	// ch <- 3  // would cause a deadlock. overfilling buffer
-	fmt.Println(<-ch)
-	fmt.Println(<-ch)
+	// ch <- 3  // would cause a deadlock. overfilling buffer. see it with tour whatif overfill
+	fmt.Fprintln(w, <-ch)
+	fmt.Fprintln(w, <-ch)
 }
 
 /*
@@ -75,69 +156,75 @@ A sender can close a channel to indicate that no more values will be sent.
 Receivers can test whether a channel has been closed by assigning a second parameter to the receive expression
 v, ok := <-ch
 */
-func rangeAndClose() {
-	c := make(chan int, 10)
-	go fibonacci(cap(c), c)
-	for i := range c {
-		fmt.Println(i)
+func rangeAndClose(w io.Writer) {
+	// cancelling ctx stops every stage. always cancel once you're done
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fibonacci never ends on its own. Take is the sender here. after 10
+	// values it calls close() on its channel, and that's what ends the range.
+	// without a close the range would wait for an 11th value forever.
+	// closeChan does the same thing by hand
+	for i := range pipeline.Take(ctx, pipeline.Fibonacci(ctx), 10) {
+		fmt.Fprintln(w, i)
 	}
+	// note only senders should close channels. not receivers. sending on a
+	// closed channel panics, and the receiver can't know if more is coming
+	// you do not have to close channels. useful for termination purposes
 }
 
-func fibonacci(n int, c chan int) {
-	x, y := 0, 1
-	for i := 0; i < n; i++ {
-		c <- x
-		x, y = y, x+y
+// closeChan is what Take does inside, spelled out. the sending goroutine
+// closes c when it has nothing more to send
+func closeChan(w io.Writer) {
+	c := make(chan int)
+	go func() {
+		for i := range 3 {
+			c <- i
+		}
+		close(c)
+	}()
+	for i := range c {
+		fmt.Fprintln(w, i)
 	}
-	// note only senders should close channels. not receivers
-	close(c)
-	// you do not have to close channels. useful for termination purposes
+	// once c is closed and empty every receive returns straight away with the
+	// zero value. ok tells that apart from a real 0 being sent
+	v, ok := <-c
+	fmt.Fprintln(w, v, ok)
 }
 
 /*
 The select statement lets a goroutine wait on multiple communication operations.
 
 A select blocks until one of its cases can run, then it executes that case. It chooses one at random if multiple are ready.
+pipeline.Fibonacci is a select between sending the next number and ctx.Done()
 */
-func selectSel() {
-	c := make(chan int)
-	quit := make(chan int)
-	go func() {
-		for i := 0; i < 10; i++ {
-			fmt.Println(<-c)
-		}
-		quit <- 0
-	}()
-	fibonacciSel(c, quit)
-}
-
-func fibonacciSel(c, quit chan int) {
-	x, y := 0, 1
-	for {
-		select {
-		case c <- x:
-			x, y = y, x+y
-		case <-quit:
-			fmt.Println("quit")
-			return
-		}
+func selectSel(w io.Writer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := pipeline.Fibonacci(ctx)
+	for i := 0; i < 10; i++ {
+		fmt.Fprintln(w, <-c)
 	}
+	// cancel does the job of the old quit channel. it is closed rather than
+	// sent on, so any number of goroutines can wait for it
+	cancel()
+	fmt.Fprintln(w, "quit")
 }
 
 // The default case in a select is run if no other case is ready.
-func defaultSelect() {
-	tick := time.Tick(100 * time.Millisecond)
-	boom := time.After(500 * time.Millisecond)
+func defaultSelect(env *lesson.Env) {
+	w := env.Out
+	tick := env.Clock.Tick(100 * time.Millisecond)
+	boom := env.Clock.After(500 * time.Millisecond)
 	for {
 		select {
 		case <-tick:
-			fmt.Println("tick.")
+			fmt.Fprintln(w, "tick.")
 		case <-boom:
-			fmt.Println("BOOM!")
+			fmt.Fprintln(w, "BOOM!")
 			return
 		default:
-			fmt.Println("    .")
-			time.Sleep(50 * time.Millisecond)
+			fmt.Fprintln(w, "    .")
+			env.Clock.Sleep(50 * time.Millisecond)
 		}
 	}
 }
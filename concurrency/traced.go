@@ -0,0 +1,114 @@
+package concurrency
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/DaveM7788/tourOfGo/chantrace"
+	"github.com/DaveM7788/tourOfGo/clock"
+	"github.com/DaveM7788/tourOfGo/lesson"
+)
+
+// traced copies of the lessons above, with every channel swapped for a
+// chantrace.Chan and every print for tr.Printf. run them with tour trace
+var traced = map[string]func(tr *chantrace.Tracer){
+	"simpleGoroutine": tracedSimpleGoroutine,
+	"channelsChan":    tracedChannelsChan,
+	"bufferedChan":    tracedBufferedChan,
+	"selectSel":       tracedSelectSel,
+}
+
+// Traced returns the traced version of a lesson, if it has one
+func Traced(name string) (func(tr *chantrace.Tracer), bool) {
+	f, ok := traced[name]
+	return f, ok
+}
+
+// TracedNames lists the lessons that have a traced version
+func TracedNames() []string {
+	var names []string
+	for n := range traced {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// logWriter turns each line a lesson prints into a log event
+type logWriter struct{ tr *chantrace.Tracer }
+
+func (w logWriter) Write(p []byte) (int, error) {
+	w.tr.Printf("%s", strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+func tracedSimpleGoroutine(tr *chantrace.Tracer) {
+	// the same say as the lesson, printing into the trace on the real clock
+	env := &lesson.Env{Out: logWriter{tr}, Clock: clock.Real}
+	done := chantrace.NewChan[bool](tr, "done", 0)
+	tr.Go(func() {
+		say(env, "world")
+		done.Send(true)
+	})
+	say(env, "hello")
+	done.Recv()
+}
+
+func tracedChannelsChan(tr *chantrace.Tracer) {
+	s := []int{7, 2, 8, -9, 4, 0}
+	c := chantrace.NewChan[int](tr, "c", 0)
+	sum := func(s []int) {
+		sum := 0
+		for _, v := range s {
+			sum += v
+		}
+		c.Send(sum)
+	}
+	tr.Go(func() { sum(s[:len(s)/2]) })
+	tr.Go(func() { sum(s[len(s)/2:]) })
+	x, _ := c.Recv()
+	y, _ := c.Recv()
+	tr.Printf("%d %d %d", x, y, x+y)
+}
+
+func tracedBufferedChan(tr *chantrace.Tracer) {
+	ch := chantrace.NewChan[int](tr, "ch", 2)
+	ch.Send(1)
+	ch.Send(2)
+	// ch.Send(3) would show up as a block that never unblocks
+	v, _ := ch.Recv()
+	tr.Printf("%d", v)
+	v, _ = ch.Recv()
+	tr.Printf("%d", v)
+}
+
+func tracedSelectSel(tr *chantrace.Tracer) {
+	ctx, cancel := tr.WithCancel(context.Background())
+	c := tracedFibonacci(tr, ctx)
+	for i := 0; i < 10; i++ {
+		v, _ := c.Recv()
+		tr.Printf("%d", v)
+	}
+	cancel()
+	tr.Printf("quit")
+}
+
+// tracedFibonacci is pipeline.Fibonacci
+func tracedFibonacci(tr *chantrace.Tracer, ctx context.Context) *chantrace.Chan[int] {
+	out := chantrace.NewChan[int](tr, "out", 0)
+	tr.Go(func() {
+		defer out.Close()
+		x, y := 0, 1
+		for {
+			// the labels are built from x, so make new cases every time round
+			switch chosen, _, _ := tr.Select(out.SendCase(x), chantrace.DoneCase(ctx)); chosen {
+			case 0:
+				x, y = y, x+y
+			case 1:
+				return
+			}
+		}
+	})
+	return out
+}
@@ -0,0 +1,82 @@
+package concurrency
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/whatif"
+)
+
+// the failure cases from the comments above, made runnable. these deadlock or
+// never finish on purpose, so only ever run them through tour whatif, which
+// gives each one its own process
+func init() {
+	for _, v := range []whatif.Variant{
+		{Name: "overfill", Lesson: "bufferedChan", Description: "a third send into a channel with room for two", Run: overfill},
+		{Name: "noclose", Lesson: "closeChan", Description: "the sender forgets to close(c)", Run: noclose},
+		{Name: "noquit", Lesson: "selectSel", Description: "the receiver never sends on quit", Run: noquit},
+		{Name: "noboom", Lesson: "defaultSelect", Description: "there is no boom channel to end the loop", Run: noboom},
+	} {
+		whatif.Register(v)
+	}
+}
+
+func overfill(w io.Writer) {
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	fmt.Fprintln(w, <-ch)
+	fmt.Fprintln(w, <-ch)
+}
+
+func noclose(w io.Writer) {
+	c := make(chan int, 10)
+	go func(n int) {
+		x, y := 0, 1
+		for i := 0; i < n; i++ {
+			c <- x
+			x, y = y, x+y
+		}
+		// close(c) would go here
+	}(cap(c))
+	for i := range c {
+		fmt.Fprintln(w, i)
+	}
+}
+
+func noquit(w io.Writer) {
+	c := make(chan int)
+	quit := make(chan int)
+	go func() {
+		for i := 0; i < 10; i++ {
+			fmt.Fprintln(w, <-c)
+		}
+		// quit <- 0 would go here
+	}()
+	x, y := 0, 1
+	for {
+		select {
+		case c <- x:
+			x, y = y, x+y
+		case <-quit:
+			fmt.Fprintln(w, "quit")
+			return
+		}
+	}
+}
+
+// noboom never deadlocks. the ticker keeps it busy, so it runs until the
+// timeout and gets its goroutines dumped instead
+func noboom(w io.Writer) {
+	tick := time.Tick(100 * time.Millisecond)
+	for {
+		select {
+		case <-tick:
+			fmt.Fprintln(w, "tick.")
+		default:
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+}
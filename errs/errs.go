@@ -0,0 +1,127 @@
+// Package errs is how the rest of the tour reports failures. errorsErr
+// printed "couldn't convert number" and threw the details away. here an
+// error keeps what was being done, to what input and where, and wraps the
+// error underneath with %w so nothing is lost. errors.Is and errors.As dig
+// through the layers, Render prints them all
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// sentinels are plain values to compare against with errors.Is. the typed
+// errors below say which one they are through their Is method, so callers
+// only need these for the common questions
+var (
+	ErrInvalid    = errors.New("invalid input")
+	ErrOutOfRange = errors.New("out of range")
+	ErrWrongType  = errors.New("wrong type")
+	ErrNotFound   = errors.New("not found")
+)
+
+// OpError says which operation failed on what input. Pos is the 1 based
+// column of the problem in Input, or 0 when it isn't known
+type OpError struct {
+	Op    string
+	Input string
+	Pos   int
+	Err   error
+}
+
+// Op wraps err with the operation and input that caused it. nil stays nil so
+// it can go straight round a call, return errs.Op("atoi", s, 0, err). with
+// Stacks on it also records who called Op
+func Op(op, input string, pos int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return withStack(&OpError{Op: op, Input: input, Pos: pos, Err: err}, 1)
+}
+
+func (e *OpError) Error() string {
+	s := e.Op + " " + strconv.Quote(e.Input)
+	if e.Pos > 0 {
+		s += fmt.Sprintf(" at col %d", e.Pos)
+	}
+	return s + ": " + e.Err.Error()
+}
+
+func (e *OpError) Unwrap() error { return e.Err }
+
+// TypeError is a type assertion that didn't match, what typeAssertion's
+// f = i.(float64) would have panicked with
+type TypeError struct {
+	Want, Got string
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("have %s, want %s", e.Got, e.Want)
+}
+
+// Is makes errors.Is(err, ErrWrongType) true
+func (e *TypeError) Is(target error) bool { return target == ErrWrongType }
+
+// Assert is the comma ok type assertion with an error instead of a bool.
+// it isn't called As so it doesn't look like errors.As, which is something
+// else entirely
+func Assert[T any](i any) (T, error) {
+	v, ok := i.(T)
+	if !ok {
+		// %T of a zero T says <nil> when T is an interface
+		return v, &TypeError{Want: reflect.TypeFor[T]().String(), Got: fmt.Sprintf("%T", i)}
+	}
+	return v, nil
+}
+
+// Atoi is strconv.Atoi with the position of the first bad character and
+// the sentinel it counts as. errors.As still finds the *strconv.NumError
+func Atoi(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err == nil {
+		return n, nil
+	}
+	sentinel, pos := ErrInvalid, 0
+	if errors.Is(err, strconv.ErrRange) {
+		sentinel = ErrOutOfRange
+	} else {
+		pos = firstNonDigit(s)
+	}
+	// two %w. the result unwraps to both
+	return 0, Op("atoi", s, pos, fmt.Errorf("%w: %w", sentinel, err))
+}
+
+func firstNonDigit(s string) int {
+	for i, r := range s {
+		if i == 0 && (r == '-' || r == '+') && len(s) > 1 {
+			continue
+		}
+		if r < '0' || r > '9' {
+			return len([]rune(s[:i])) + 1
+		}
+	}
+	return 0
+}
+
+// Collector gathers the errors from a loop that keeps going after a failure
+type Collector struct {
+	errs []error
+}
+
+// Add keeps err if it isn't nil
+func (c *Collector) Add(err error) {
+	if err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+// Len is how many errors have been added
+func (c *Collector) Len() int { return len(c.errs) }
+
+// Err joins everything added with errors.Join, nil when nothing went wrong.
+// errors.Is and errors.As look at every one of them
+func (c *Collector) Err() error {
+	return errors.Join(c.errs...)
+}
@@ -0,0 +1,117 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAtoi(t *testing.T) {
+	for _, tt := range []struct {
+		in       string
+		sentinel error
+		msg      string
+	}{
+		{"12a", ErrInvalid, `atoi "12a" at col 3: invalid input: strconv.Atoi: parsing "12a": invalid syntax`},
+		{"-", ErrInvalid, `atoi "-" at col 1: invalid input: strconv.Atoi: parsing "-": invalid syntax`},
+		{"99999999999999999999", ErrOutOfRange, `atoi "99999999999999999999": out of range: strconv.Atoi: parsing "99999999999999999999": value out of range`},
+	} {
+		_, err := Atoi(tt.in)
+		if !errors.Is(err, tt.sentinel) {
+			t.Errorf("Atoi(%q) = %v, want it to be %v", tt.in, err, tt.sentinel)
+		}
+		var ne *strconv.NumError
+		if !errors.As(err, &ne) {
+			t.Errorf("Atoi(%q): no *strconv.NumError in %v", tt.in, err)
+		}
+		if err.Error() != tt.msg {
+			t.Errorf("Atoi(%q) error\n\t%s\nwant\n\t%s", tt.in, err, tt.msg)
+		}
+	}
+	if n, err := Atoi("-42"); n != -42 || err != nil {
+		t.Errorf("Atoi(-42) = %d, %v", n, err)
+	}
+}
+
+func TestAssert(t *testing.T) {
+	if _, err := Assert[float64](any("hi")); !errors.Is(err, ErrWrongType) {
+		t.Errorf("got %v, want ErrWrongType", err)
+	} else if err.Error() != "have string, want float64" {
+		t.Errorf("message %q", err)
+	}
+	if v, err := Assert[int](any(3)); v != 3 || err != nil {
+		t.Errorf("Assert[int](3) = %v, %v", v, err)
+	}
+
+	// interfaces used to come out as <nil>
+	if _, err := Assert[fmt.Stringer](any(3)); err == nil || err.Error() != "have int, want fmt.Stringer" {
+		t.Errorf("Assert[fmt.Stringer](3) = %v", err)
+	}
+	if _, err := Assert[error](any(nil)); err == nil || err.Error() != "have <nil>, want error" {
+		t.Errorf("Assert[error](nil) = %v", err)
+	}
+	if s, err := Assert[fmt.Stringer](any(time.Second)); s != time.Second || err != nil {
+		t.Errorf("Assert[fmt.Stringer](time.Second) = %v, %v", s, err)
+	}
+}
+
+func TestCollector(t *testing.T) {
+	var c Collector
+	c.Add(nil)
+	if c.Err() != nil || c.Len() != 0 {
+		t.Fatalf("a nil error was kept")
+	}
+	_, err1 := Atoi("x")
+	_, err2 := Assert[int](any("y"))
+	c.Add(err1)
+	c.Add(err2)
+	if err := c.Err(); !errors.Is(err, ErrInvalid) || !errors.Is(err, ErrWrongType) {
+		t.Errorf("joined error %v lost one of its parts", err)
+	}
+}
+
+func TestChain(t *testing.T) {
+	_, err := Atoi("x")
+	err = fmt.Errorf("reading config: %w", err)
+	var types []string
+	for _, e := range Chain(err) {
+		types = append(types, fmt.Sprintf("%T", e))
+	}
+	want := "*fmt.wrapError *errs.OpError *fmt.wrapErrors *errors.errorString *strconv.NumError *errors.errorString"
+	if got := strings.Join(types, " "); got != want {
+		t.Errorf("Chain\n\t%s\nwant\n\t%s", got, want)
+	}
+}
+
+func outer() error { return WithStack(fmt.Errorf("outer: %w", inner())) }
+func inner() error { return WithStack(errors.New("boom")) }
+
+func TestStackInnermost(t *testing.T) {
+	Stacks = true
+	defer func() { Stacks = false }()
+
+	frames := Stack(outer())
+	if len(frames) == 0 {
+		t.Fatal("no stack")
+	}
+	// the innermost WithStack was called from inner, so that's the top frame
+	if f := frames[0].Function; !strings.HasSuffix(f, ".inner") {
+		t.Errorf("top frame is %s, want inner", f)
+	}
+	if Stack(errors.New("plain")) != nil {
+		t.Error("a plain error has a stack")
+	}
+}
+
+func TestStacksOff(t *testing.T) {
+	err := errors.New("boom")
+	if WithStack(err) != err {
+		t.Error("WithStack wrapped err with Stacks off")
+	}
+	if WithStack(nil) != nil {
+		t.Error("WithStack(nil) isn't nil")
+	}
+}
@@ -0,0 +1,73 @@
+package errs
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Chain lists err and everything it wraps, outermost first. joined errors
+// and fmt.Errorf with several %w wrap more than one, they're all included
+// depth first
+func Chain(err error) []error {
+	var out []error
+	walk(err, 0, func(e error, _ int) { out = append(out, e) })
+	return out
+}
+
+func walk(err error, depth int, f func(error, int)) {
+	if err == nil {
+		return
+	}
+	// stacks are shown separately, not as a layer
+	if se, ok := err.(*stackError); ok {
+		walk(se.err, depth, f)
+		return
+	}
+	f(err, depth)
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		walk(u.Unwrap(), depth+1, f)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			walk(e, depth+1, f)
+		}
+	}
+}
+
+// Render writes the chain as an indented tree, one layer per line with its
+// type. each line only shows what that layer added to the message, the rest
+// is on the lines below it. with Stacks on, where it came from follows
+func Render(w io.Writer, err error) {
+	walk(err, 0, func(e error, depth int) {
+		fmt.Fprintf(w, "%s%T: %s\n", strings.Repeat("  ", depth), e, own(e))
+	})
+	for _, f := range Stack(err) {
+		fmt.Fprintf(w, "  at %s %s:%d\n", f.Function, f.File, f.Line)
+	}
+}
+
+// own is the part of e's message that doesn't come from the errors it wraps.
+// fmt.Errorf("atoi: %w", err) gives "atoi: " + err.Error(), so cut that off
+func own(e error) string {
+	msg := e.Error()
+	var inner []error
+	switch u := e.(type) {
+	case interface{ Unwrap() error }:
+		inner = []error{u.Unwrap()}
+	case interface{ Unwrap() []error }:
+		inner = u.Unwrap()
+	}
+	if len(inner) == 1 && inner[0] != nil {
+		if s, ok := strings.CutSuffix(msg, inner[0].Error()); ok && s != "" {
+			return strings.TrimSuffix(strings.TrimSuffix(s, " "), ":")
+		}
+		if msg == inner[0].Error() {
+			return "(wraps)"
+		}
+	}
+	if len(inner) > 1 {
+		return fmt.Sprintf("(%d errors)", len(inner))
+	}
+	return msg
+}
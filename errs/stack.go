@@ -0,0 +1,63 @@
+package errs
+
+import (
+	"errors"
+	"runtime"
+)
+
+// Stacks turns on stack capture in WithStack. it costs a runtime.Callers per
+// error so it's off unless you're hunting for where something came from
+var Stacks = false
+
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+func (e *stackError) Error() string    { return e.err.Error() }
+func (e *stackError) Unwrap() error    { return e.err }
+func (e *stackError) stack() []uintptr { return e.pcs }
+
+// WithStack records where it was called from when Stacks is on. otherwise,
+// or for a nil err, it returns err untouched
+func WithStack(err error) error {
+	return withStack(err, 1)
+}
+
+// skip is how many callers above withStack's caller to leave out
+func withStack(err error, skip int) error {
+	if err == nil || !Stacks {
+		return err
+	}
+	pcs := make([]uintptr, 32)
+	// skip runtime.Callers and withStack too
+	pcs = pcs[:runtime.Callers(2+skip, pcs)]
+	return &stackError{err: err, pcs: pcs}
+}
+
+// Stack returns the frames saved by the innermost WithStack in err's chain,
+// the one nearest where the error started. nil if there isn't one
+func Stack(err error) []runtime.Frame {
+	// errors.As stops at the first, outermost one. keep looking inside it
+	// until there are no more
+	var st *stackError
+	if !errors.As(err, &st) {
+		return nil
+	}
+	for {
+		var inner *stackError
+		if !errors.As(st.err, &inner) {
+			break
+		}
+		st = inner
+	}
+	var out []runtime.Frame
+	frames := runtime.CallersFrames(st.stack())
+	for {
+		f, more := frames.Next()
+		out = append(out, f)
+		if !more {
+			return out
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package exercise
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Case is the result of one test case
+type Case struct {
+	Name   string
+	Passed bool
+	Output string // whatever the test logged, usually why it failed
+}
+
+// Result is what Check found out about a solution
+type Result struct {
+	// BuildOutput is set when the solution didn't compile. there are no
+	// cases in that case
+	BuildOutput string
+	Cases       []Case
+}
+
+// Passed reports whether the solution compiled and every case passed
+func (r *Result) Passed() bool {
+	if r.BuildOutput != "" || len(r.Cases) == 0 {
+		return false
+	}
+	for _, c := range r.Cases {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Check compiles src against e's hidden tests and runs them. it needs the go
+// command on PATH. the error is only for things going wrong around the
+// solution. a solution that doesn't build or fails tests is a Result
+func Check(ctx context.Context, e Exercise, src []byte) (*Result, error) {
+	dir, err := os.MkdirTemp("", "tour-check-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"go.mod":           "module scratch\n\ngo 1.23\n",
+		"solution.go":      string(src),
+		"solution_test.go": e.Tests,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "test", "-json", "-count=1", ".")
+	cmd.Dir = dir
+	// the scratch module must not pick up a go.work from wherever tour runs
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	runErr := cmd.Run()
+
+	// go test exits 1 when tests fail, so only give up if it didn't run at all
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return nil, fmt.Errorf("running go test: %w", runErr)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	cases, buildOut, err := parseEvents(&stdout)
+	if err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		// older go versions print build errors on stderr, newer ones put
+		// them in the json as build-output events
+		out := strings.TrimSpace(buildOut + stderr.String())
+		if out == "" {
+			out = "no tests ran"
+		}
+		// paths in the temp dir mean nothing to the learner
+		return &Result{BuildOutput: strings.ReplaceAll(out, dir+string(filepath.Separator), "")}, nil
+	}
+	return &Result{Cases: cases}, nil
+}
+
+// testEvent is one line of go test -json. see go doc test2json
+type testEvent struct {
+	Action string
+	Test   string
+	Output string
+}
+
+// parseEvents turns go test -json output into cases. only the leaves count,
+// so TestSplit/sum=17 is a case but TestSplit is not if it has subtests.
+// it also returns any compiler output
+func parseEvents(r io.Reader) ([]Case, string, error) {
+	var order []string
+	var build strings.Builder
+	results := map[string]*Case{}
+	dec := json.NewDecoder(r)
+	for {
+		var ev testEvent
+		if err := dec.Decode(&ev); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, "", fmt.Errorf("reading go test output: %w", err)
+		}
+		if ev.Action == "build-output" {
+			build.WriteString(ev.Output)
+			continue
+		}
+		if ev.Test == "" {
+			continue
+		}
+		c, ok := results[ev.Test]
+		if !ok {
+			c = &Case{Name: ev.Test}
+			results[ev.Test] = c
+			order = append(order, ev.Test)
+		}
+		switch ev.Action {
+		case "output":
+			// drop go test's own === RUN / --- PASS chatter
+			line := strings.TrimSpace(ev.Output)
+			if !strings.HasPrefix(line, "===") && !strings.HasPrefix(line, "---") {
+				c.Output += ev.Output
+			}
+		case "pass":
+			c.Passed = true
+		}
+	}
+
+	var cases []Case
+	for _, name := range order {
+		parent := false
+		for _, other := range order {
+			if strings.HasPrefix(other, name+"/") {
+				parent = true
+				break
+			}
+		}
+		if !parent {
+			cases = append(cases, *results[name])
+		}
+	}
+	return cases, build.String(), nil
+}
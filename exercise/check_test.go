@@ -0,0 +1,133 @@
+package exercise
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestParseEvents(t *testing.T) {
+	out := `{"Action":"start","Package":"scratch"}
+{"Action":"run","Test":"TestSplit"}
+{"Action":"output","Test":"TestSplit","Output":"=== RUN   TestSplit\n"}
+{"Action":"run","Test":"TestSplit/sum=17"}
+{"Action":"output","Test":"TestSplit/sum=17","Output":"=== RUN   TestSplit/sum=17\n"}
+{"Action":"output","Test":"TestSplit/sum=17","Output":"    solution_test.go:9: got 7, 10\n"}
+{"Action":"output","Test":"TestSplit/sum=17","Output":"--- FAIL: TestSplit/sum=17 (0.00s)\n"}
+{"Action":"fail","Test":"TestSplit/sum=17"}
+{"Action":"run","Test":"TestSplit/sum=4"}
+{"Action":"pass","Test":"TestSplit/sum=4"}
+{"Action":"fail","Test":"TestSplit"}
+{"Action":"run","Test":"TestSqrt"}
+{"Action":"pass","Test":"TestSqrt"}
+{"Action":"fail","Package":"scratch"}
+`
+	cases, build, err := parseEvents(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build != "" {
+		t.Errorf("build output %q", build)
+	}
+	// TestSplit has subtests so only they count
+	want := []Case{
+		{Name: "TestSplit/sum=17", Output: "    solution_test.go:9: got 7, 10\n"},
+		{Name: "TestSplit/sum=4", Passed: true},
+		{Name: "TestSqrt", Passed: true},
+	}
+	if len(cases) != len(want) {
+		t.Fatalf("got %d cases: %+v", len(cases), cases)
+	}
+	for i := range want {
+		if cases[i] != want[i] {
+			t.Errorf("case %d = %+v, want %+v", i, cases[i], want[i])
+		}
+	}
+}
+
+func TestParseEventsBuildOutput(t *testing.T) {
+	out := `{"ImportPath":"scratch","Action":"build-output","Output":"./solution.go:3:1: syntax error\n"}
+{"ImportPath":"scratch","Action":"build-fail"}
+`
+	cases, build, err := parseEvents(strings.NewReader(out))
+	if err != nil || len(cases) != 0 || build != "./solution.go:3:1: syntax error\n" {
+		t.Errorf("got %v, %q, %v", cases, build, err)
+	}
+	if _, _, err := parseEvents(strings.NewReader("not json")); err == nil {
+		t.Error("junk was accepted")
+	}
+}
+
+func TestResultPassed(t *testing.T) {
+	for _, tt := range []struct {
+		r    Result
+		want bool
+	}{
+		{Result{}, false},
+		{Result{BuildOutput: "syntax error"}, false},
+		{Result{Cases: []Case{{Passed: true}, {Passed: true}}}, true},
+		{Result{Cases: []Case{{Passed: true}, {Passed: false}}}, false},
+	} {
+		if got := tt.r.Passed(); got != tt.want {
+			t.Errorf("%+v.Passed() = %v, want %v", tt.r, got, tt.want)
+		}
+	}
+}
+
+var double = Exercise{
+	Name: "double",
+	Stub: `package scratch
+
+func Double(n int) int { return 0 }
+`,
+	Tests: `package scratch
+
+import "testing"
+
+func TestDouble(t *testing.T) {
+	for _, n := range []int{0, 2} {
+		t.Run("", func(t *testing.T) {
+			if Double(n) != 2*n {
+				t.Errorf("Double(%d) = %d", n, Double(n))
+			}
+		})
+	}
+}
+`,
+}
+
+func TestCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("no go command")
+	}
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name   string
+		src    string
+		passed bool
+		build  bool
+	}{
+		{"stub", double.Stub, false, false},
+		{"answer", "package scratch\n\nfunc Double(n int) int { return n * 2 }\n", true, false},
+		{"broken", "package scratch\n\nfunc Double(n int) int { return n * }\n", false, true},
+	} {
+		r, err := Check(ctx, double, []byte(tt.src))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if r.Passed() != tt.passed || (r.BuildOutput != "") != tt.build {
+			t.Errorf("%s: got %+v", tt.name, r)
+		}
+		// the stub passes Double(0) by luck and fails Double(2)
+		if tt.name == "stub" && (len(r.Cases) != 2 || !r.Cases[0].Passed || r.Cases[1].Passed) {
+			t.Errorf("stub cases %+v", r.Cases)
+		}
+		if tt.build && strings.Contains(r.BuildOutput, "tour-check-") {
+			t.Errorf("build output mentions the temp dir: %s", r.BuildOutput)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package exercise holds the write-it-yourself versions of the lessons.
+// an exercise is a stub file the learner fills in plus a hidden test file.
+// tour check drops both into a throwaway module and runs go test on them
+package exercise
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Exercise is registered by the lesson package it comes from, the same way
+// lessons are
+type Exercise struct {
+	Name        string // what you type after `tour check`
+	Lesson      string // the lesson the exercise is based on
+	Description string
+
+	// Stub is the scratch file handed to the learner. it has to be
+	// package scratch and compile, even if the answers are wrong
+	Stub string
+
+	// Tests is a _test.go file in package scratch. learners never see it.
+	// use t.Run for each case so check can report them one by one
+	Tests string
+}
+
+var registry = map[string]Exercise{}
+
+// Register adds e to the registry. it panics on a duplicate or incomplete
+// exercise, same as lesson.Register
+func Register(e Exercise) {
+	if e.Name == "" || e.Stub == "" || e.Tests == "" {
+		panic(fmt.Sprintf("exercise: Register called with incomplete exercise %q", e.Name))
+	}
+	if _, dup := registry[e.Name]; dup {
+		panic("exercise: Register called twice for exercise " + e.Name)
+	}
+	registry[e.Name] = e
+}
+
+// Lookup finds an exercise by name
+func Lookup(name string) (Exercise, bool) {
+	e, ok := registry[name]
+	return e, ok
+}
+
+// All returns every exercise sorted by name
+func All() []Exercise {
+	all := make([]Exercise, 0, len(registry))
+	for _, e := range registry {
+		all = append(all, e)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
@@ -0,0 +1,44 @@
+package main
+
+import "github.com/DaveM7788/tourOfGo/exercise"
+
+func init() {
+	exercise.Register(exercise.Exercise{
+		Name:        "split",
+		Lesson:      "helloWorld",
+		Description: "split a number into 4/9 and the rest with named results",
+		Stub: `package scratch
+
+// split returns x, four ninths of sum (rounded down), and y, whatever is left
+// over. try using named results and a naked return
+func split(sum int) (x, y int) {
+	// TODO
+	return
+}
+`,
+		Tests: `package scratch
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	for _, tc := range []struct{ sum, x, y int }{
+		{17, 7, 10},
+		{9, 4, 5},
+		{0, 0, 0},
+		{100, 44, 56},
+		{-18, -8, -10},
+	} {
+		t.Run(fmt.Sprintf("sum=%d", tc.sum), func(t *testing.T) {
+			x, y := split(tc.sum)
+			if x != tc.x || y != tc.y {
+				t.Errorf("split(%d) = %d, %d. want %d, %d", tc.sum, x, y, tc.x, tc.y)
+			}
+		})
+	}
+}
+`,
+	})
+}
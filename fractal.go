@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/fractal"
+)
+
+// fractalCmd draws the Mandelbrot set, or a Julia set, in the terminal or to
+// a file. --bench times the same render with 1, 2 and all the CPUs
+func fractalCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("fractal", flag.ContinueOnError)
+	julia := fs.String("julia", "", "draw the Julia set for `c`, eg. -0.8+0.156i")
+	center := fs.String("center", "", "complex number in the middle of the picture")
+	width := fs.Float64("width", 0, "how much of the real axis to fit across")
+	zoom := fs.Float64("zoom", 1, "zoom in this many times")
+	iter := fs.Int("iter", 256, "give up on a point after this many iterations")
+	palette := fs.String("palette", "fire", "one of "+strings.Join(fractal.PaletteNames(), ", "))
+	size := fs.String("size", "", "image size, `WxH`. 800x600 for files, 128x64 for the terminal")
+	workers := fs.Int("workers", 0, "goroutines drawing tiles. 0 means one per CPU")
+	tile := fs.Int("tile", 64, "tile size in pixels")
+	out := fs.String("out", "", "save to this file instead of drawing in the terminal. .png, .ppm or .pgm")
+	cols := fs.Int("cols", 0, "how many characters wide to draw in the terminal. 0 means the image width")
+	bench := fs.Bool("bench", false, "time the render with different numbers of workers instead")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := fractal.Config{View: fractal.DefaultView, MaxIter: *iter, TileSize: *tile, Workers: *workers}
+	if *julia != "" {
+		c, err := strconv.ParseComplex(*julia, 128)
+		if err != nil {
+			return fmt.Errorf("--julia: %q isn't a complex number", *julia)
+		}
+		cfg.Set = fractal.Julia(c)
+		cfg.View = fractal.Viewport{Width: 3.2}
+	}
+	if *center != "" {
+		c, err := strconv.ParseComplex(*center, 128)
+		if err != nil {
+			return fmt.Errorf("--center: %q isn't a complex number", *center)
+		}
+		cfg.View.Center = c
+	}
+	if *width > 0 {
+		cfg.View.Width = *width
+	}
+	cfg.View.Zoom = *zoom
+	p, ok := fractal.Palettes[*palette]
+	if !ok {
+		return fmt.Errorf("no palette %q. try one of %s", *palette, strings.Join(fractal.PaletteNames(), ", "))
+	}
+	cfg.Palette = p
+
+	if *size == "" {
+		*size = "128x64"
+		if *out != "" || *bench {
+			*size = "800x600"
+		}
+	}
+	if _, err := fmt.Sscanf(*size, "%dx%d", &cfg.Width, &cfg.Height); err != nil {
+		return fmt.Errorf("--size wants something like 800x600, not %q", *size)
+	}
+
+	if *bench {
+		return fractalBench(w, cfg)
+	}
+	img, _, err := fractal.Render(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	return saveImage(w, img, *out, *cols)
+}
+
+// fractalBench renders the same picture with 1, 2 and NumCPU workers, a few
+// times each, and keeps the fastest. speedup is against one worker
+func fractalBench(w io.Writer, cfg fractal.Config) error {
+	counts := []int{1, 2}
+	if n := runtime.NumCPU(); n > 2 {
+		counts = append(counts, n)
+	}
+	fmt.Fprintf(w, "%s %dx%d, %d iterations, %d pixel tiles\n\n", cfg.Set, cfg.Width, cfg.Height, cfg.MaxIter, cfg.TileSize)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "workers\tbest\tspeedup\ttiles each\t")
+	var base time.Duration
+	for _, n := range counts {
+		cfg.Workers = n
+		var best time.Duration
+		var stats fractal.Stats
+		for range 3 {
+			_, s, err := fractal.Render(context.Background(), cfg)
+			if err != nil {
+				return err
+			}
+			if best == 0 || s.Elapsed < best {
+				best, stats = s.Elapsed, s
+			}
+		}
+		if base == 0 {
+			base = best
+		}
+		fmt.Fprintf(tw, "%d\t%v\t%.2fx\t%v\t\n", n, best.Round(time.Microsecond), float64(base)/float64(best), stats.Workers)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,97 @@
+// Package fractal draws the Mandelbrot and Julia sets. basicTypes has
+// cmplx.Sqrt(-5 + 12i) and then never touches complex128 again, but this is
+// what it's for. take a complex number z, square it, add c, and repeat. for
+// some starting points it flies off to infinity, for others it stays put
+// forever. colour each pixel by how quickly it escaped and you get these
+package fractal
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// Set says which fractal to draw
+type Set struct {
+	// Julia picks the Julia set for C. otherwise it's the Mandelbrot set
+	Julia bool
+	C     complex128
+}
+
+// Mandelbrot starts every pixel at z = 0 with c set to the pixel
+var Mandelbrot = Set{}
+
+// Julia starts z at the pixel with the same c everywhere. each c gives a
+// different picture. -0.8+0.156i is a nice one
+func Julia(c complex128) Set {
+	return Set{Julia: true, C: c}
+}
+
+func (s Set) String() string {
+	if s.Julia {
+		return fmt.Sprintf("julia %v", s.C)
+	}
+	return "mandelbrot"
+}
+
+// Escape runs z = z*z + c from z until |z| > 2, after which it's certain to
+// run off to infinity, or until maxIter is used up. it returns a smoothed
+// count, so neighbouring pixels don't jump in colour, and whether it escaped
+// at all. points that never escape are in the set
+func Escape(z, c complex128, maxIter int) (float64, bool) {
+	for i := 0; i < maxIter; i++ {
+		// real(z)*real(z) + imag(z)*imag(z) is |z| squared without the
+		// square root cmplx.Abs takes
+		if r2 := real(z)*real(z) + imag(z)*imag(z); r2 > 4 {
+			// knock off the fraction of a step it overshot by
+			return float64(i) + 1 - math.Log(math.Log(math.Sqrt(r2)))/math.Ln2, true
+		}
+		z = z*z + c
+	}
+	return float64(maxIter), false
+}
+
+// Viewport is the part of the complex plane that ends up in the picture
+type Viewport struct {
+	Center complex128
+	Width  float64 // how much of the real axis fits across the image
+	Zoom   float64 // divides Width. 0 counts as 1
+}
+
+// DefaultView frames the whole of the Mandelbrot set
+var DefaultView = Viewport{Center: -0.5, Width: 3.5}
+
+// Point is the complex number at pixel x, y of a w by h image. y goes down
+// the screen but the imaginary axis goes up, hence the minus
+func (v Viewport) Point(x, y, w, h int) complex128 {
+	zoom := v.Zoom
+	if zoom == 0 {
+		zoom = 1
+	}
+	scale := v.Width / zoom / float64(w)
+	re := real(v.Center) + (float64(x)-float64(w)/2+0.5)*scale
+	im := imag(v.Center) - (float64(y)-float64(h)/2+0.5)*scale
+	return complex(re, im)
+}
+
+// Iterate is the escape count at one point of the set
+func (s Set) Iterate(p complex128, maxIter int) (float64, bool) {
+	if s.Julia {
+		return Escape(p, s.C, maxIter)
+	}
+	// the main cardioid and the circle to its left are inside the set and
+	// would take every iteration to find that out. checking is cheap
+	if inCardioid(p) {
+		return float64(maxIter), false
+	}
+	return Escape(0, p, maxIter)
+}
+
+func inCardioid(c complex128) bool {
+	x, y := real(c), imag(c)
+	q := (x-0.25)*(x-0.25) + y*y
+	if q*(q+(x-0.25)) <= 0.25*y*y {
+		return true
+	}
+	return cmplx.Abs(c+1) <= 0.25
+}
@@ -0,0 +1,41 @@
+package fractal
+
+import (
+	"image/color"
+	"maps"
+	"math"
+	"slices"
+)
+
+// Palette turns how quickly a point escaped into a colour. t runs from 0 for
+// straight away to 1 for only just. points in the set are always black
+type Palette func(t float64) color.RGBA
+
+// Palettes are the palettes by name
+var Palettes = map[string]Palette{
+	"gray": func(t float64) color.RGBA {
+		v := uint8(255 * t)
+		return color.RGBA{v, v, v, 255}
+	},
+	"fire": func(t float64) color.RGBA {
+		return color.RGBA{channel(3 * t), channel(3*t - 1), channel(3*t - 2), 255}
+	},
+	"ocean": func(t float64) color.RGBA {
+		return color.RGBA{channel(2*t - 1), channel(1.5 * t), channel(0.3 + t), 255}
+	},
+	// round the colour wheel a few times so there's detail everywhere
+	"rainbow": func(t float64) color.RGBA {
+		h := math.Mod(t*5, 1) * 6
+		return color.RGBA{channel(math.Abs(h-3) - 1), channel(2 - math.Abs(h-2)), channel(2 - math.Abs(h-4)), 255}
+	},
+}
+
+// channel turns 0..1 into 0..255, clamping anything outside
+func channel(v float64) uint8 {
+	return uint8(255*math.Max(0, math.Min(1, v)) + 0.5)
+}
+
+// PaletteNames lists Palettes in order
+func PaletteNames() []string {
+	return slices.Sorted(maps.Keys(Palettes))
+}
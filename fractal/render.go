@@ -0,0 +1,116 @@
+package fractal
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Config is everything Render needs
+type Config struct {
+	Set           Set
+	View          Viewport
+	Width, Height int
+	MaxIter       int     // 0 means 256
+	Palette       Palette // nil means gray
+
+	// the image is cut into TileSize squares and Workers goroutines take
+	// tiles off a channel until there are none left. 0 means 64 pixel tiles
+	// and a worker per CPU
+	TileSize int
+	Workers  int
+}
+
+// Stats says how the work was shared out
+type Stats struct {
+	Tiles   int
+	Workers []int // how many tiles each worker did
+	Elapsed time.Duration
+}
+
+// Render draws the fractal. tiles are handed out one at a time rather than
+// splitting the picture into one big piece per worker, because the points in
+// the set take MaxIter iterations and the rest can take 1. whoever got the
+// middle of the picture would be left working long after the others stopped
+func Render(ctx context.Context, cfg Config) (*image.RGBA, Stats, error) {
+	if cfg.Width < 1 || cfg.Height < 1 {
+		return nil, Stats{}, fmt.Errorf("fractal: can't draw a %dx%d image", cfg.Width, cfg.Height)
+	}
+	if cfg.MaxIter <= 0 {
+		cfg.MaxIter = 256
+	}
+	if cfg.Palette == nil {
+		cfg.Palette = Palettes["gray"]
+	}
+	if cfg.TileSize <= 0 {
+		cfg.TileSize = 64
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+
+	start := time.Now()
+	img := image.NewRGBA(image.Rect(0, 0, cfg.Width, cfg.Height))
+	var tiles []image.Rectangle
+	for y := 0; y < cfg.Height; y += cfg.TileSize {
+		for x := 0; x < cfg.Width; x += cfg.TileSize {
+			tiles = append(tiles, image.Rect(x, y, x+cfg.TileSize, y+cfg.TileSize).Intersect(img.Rect))
+		}
+	}
+
+	// buffered so the whole queue can be loaded up front
+	queue := make(chan image.Rectangle, len(tiles))
+	for _, t := range tiles {
+		queue <- t
+	}
+	close(queue)
+
+	stats := Stats{Tiles: len(tiles), Workers: make([]int, cfg.Workers)}
+	var wg sync.WaitGroup
+	for i := range cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range queue {
+				if ctx.Err() != nil {
+					return
+				}
+				// every tile is a different part of img, so the workers
+				// never write the same pixel and don't need a lock
+				drawTile(img, t, cfg)
+				// each worker only touches its own counter, so this is race
+				// free without a lock. that relies on i being a new variable
+				// every time round the loop, which it is since go 1.22. before
+				// that every worker would have shared one i
+				stats.Workers[i]++
+			}
+		}()
+	}
+	wg.Wait()
+	stats.Elapsed = time.Since(start)
+	if err := ctx.Err(); err != nil {
+		return nil, stats, err
+	}
+	return img, stats, nil
+}
+
+func drawTile(img *image.RGBA, t image.Rectangle, cfg Config) {
+	logMax := math.Log(float64(cfg.MaxIter) + 1)
+	for y := t.Min.Y; y < t.Max.Y; y++ {
+		for x := t.Min.X; x < t.Max.X; x++ {
+			p := cfg.View.Point(x, y, cfg.Width, cfg.Height)
+			n, escaped := cfg.Set.Iterate(p, cfg.MaxIter)
+			c := color.RGBA{A: 255}
+			if escaped {
+				// a log scale, most points escape in the first few steps
+				c = cfg.Palette(math.Log(math.Max(n, 0)+1) / logMax)
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package fractal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"runtime"
+	"testing"
+)
+
+func TestRenderSameForAnyWorkers(t *testing.T) {
+	cfg := Config{Set: Mandelbrot, View: DefaultView, Width: 97, Height: 61, MaxIter: 64, TileSize: 16}
+	var want *image.RGBA
+	for _, workers := range []int{1, 2, 5} {
+		cfg.Workers = workers
+		img, stats, err := Render(context.Background(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 97x61 in 16 pixel tiles is 7 by 4, the last row and column short
+		if stats.Tiles != 28 {
+			t.Errorf("%d tiles, want 28", stats.Tiles)
+		}
+		done := 0
+		for _, n := range stats.Workers {
+			done += n
+		}
+		if len(stats.Workers) != workers || done != stats.Tiles {
+			t.Errorf("%d workers drew %d tiles: %v", workers, done, stats.Workers)
+		}
+		if want == nil {
+			want = img
+		} else if string(img.Pix) != string(want.Pix) {
+			t.Errorf("%d workers drew a different picture to 1", workers)
+		}
+	}
+	// the middle of the default view is in the set, so black
+	if c := want.RGBAAt(cfg.Width/2, cfg.Height/2); c.R|c.G|c.B != 0 {
+		t.Errorf("centre pixel is %v", c)
+	}
+}
+
+func TestRenderBadSize(t *testing.T) {
+	if _, _, err := Render(context.Background(), Config{Width: 0, Height: 10}); err == nil {
+		t.Error("a 0 pixel wide image was accepted")
+	}
+}
+
+func TestRenderCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := Render(ctx, Config{Set: Mandelbrot, View: DefaultView, Width: 64, Height: 64})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	workers := []int{1, 2}
+	if n := runtime.NumCPU(); n > 2 {
+		workers = append(workers, n)
+	}
+	for _, w := range workers {
+		b.Run(fmt.Sprintf("workers=%d", w), func(b *testing.B) {
+			cfg := Config{Set: Mandelbrot, View: DefaultView, Width: 256, Height: 256, Workers: w}
+			for b.Loop() {
+				if _, _, err := Render(context.Background(), cfg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
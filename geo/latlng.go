@@ -0,0 +1,170 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// LatLng is a position on the earth in degrees. north and east are positive.
+// it used to be the Vertex{Lat, Long} in the maps lesson
+type LatLng struct {
+	Lat, Lng float64
+}
+
+// EarthRadius is the mean radius of the earth in km. haversine treats the
+// earth as a sphere this size
+const EarthRadius = 6371.0088
+
+// the WGS-84 ellipsoid, which is what GPS uses. Vincenty works on this
+const (
+	wgs84A = 6378.137              // equatorial radius in km
+	wgs84F = 1 / 298.257223563     // flattening
+	wgs84B = wgs84A * (1 - wgs84F) // polar radius in km
+)
+
+// Valid reports whether p is a real place. latitude has to be within
+// +-90 and longitude within +-180
+func (p LatLng) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+func rad(deg float64) float64 { return deg * math.Pi / 180 }
+func deg(rad float64) float64 { return rad * 180 / math.Pi }
+
+// normLng wraps a longitude back into [-180, 180)
+func normLng(lng float64) float64 {
+	return math.Mod(math.Mod(lng+180, 360)+360, 360) - 180
+}
+
+// Haversine returns the great circle distance from p to q in km, treating the
+// earth as a sphere. good to about 0.5%, which is plenty for "how far is it"
+func (p LatLng) Haversine(q LatLng) float64 {
+	φ1, φ2 := rad(p.Lat), rad(q.Lat)
+	dφ, dλ := φ2-φ1, rad(q.Lng-p.Lng)
+	a := math.Sin(dφ/2)*math.Sin(dφ/2) + math.Cos(φ1)*math.Cos(φ2)*math.Sin(dλ/2)*math.Sin(dλ/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// ErrNoConvergence is returned by Vincenty for points that are nearly
+// opposite each other on the globe, where the formula can't settle
+var ErrNoConvergence = errors.New("geo: vincenty didn't converge, points are nearly antipodal")
+
+// Vincenty returns the distance from p to q in km on the WGS-84 ellipsoid.
+// it's accurate to well under a millimetre but can fail for nearly antipodal
+// points, in which case fall back to Haversine
+func (p LatLng) Vincenty(q LatLng) (float64, error) {
+	L := rad(q.Lng - p.Lng)
+	U1 := math.Atan((1 - wgs84F) * math.Tan(rad(p.Lat)))
+	U2 := math.Atan((1 - wgs84F) * math.Tan(rad(q.Lat)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	λ := L
+	var sinσ, cosσ, σ, cos2α, cos2σm float64
+	for i := 0; ; i++ {
+		if i == 200 {
+			return 0, ErrNoConvergence
+		}
+		sinλ, cosλ := math.Sincos(λ)
+		sinσ = math.Hypot(cosU2*sinλ, cosU1*sinU2-sinU1*cosU2*cosλ)
+		if sinσ == 0 {
+			return 0, nil // same point
+		}
+		cosσ = sinU1*sinU2 + cosU1*cosU2*cosλ
+		σ = math.Atan2(sinσ, cosσ)
+		sinα := cosU1 * cosU2 * sinλ / sinσ
+		cos2α = 1 - sinα*sinα
+		cos2σm = 0 // both points on the equator
+		if cos2α != 0 {
+			cos2σm = cosσ - 2*sinU1*sinU2/cos2α
+		}
+		C := wgs84F / 16 * cos2α * (4 + wgs84F*(4-3*cos2α))
+		prev := λ
+		λ = L + (1-C)*wgs84F*sinα*(σ+C*sinσ*(cos2σm+C*cosσ*(-1+2*cos2σm*cos2σm)))
+		if math.Abs(λ-prev) < 1e-12 {
+			break
+		}
+	}
+
+	u2 := cos2α * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+	A := 1 + u2/16384*(4096+u2*(-768+u2*(320-175*u2)))
+	B := u2 / 1024 * (256 + u2*(-128+u2*(74-47*u2)))
+	dσ := B * sinσ * (cos2σm + B/4*(cosσ*(-1+2*cos2σm*cos2σm)-B/6*cos2σm*(-3+4*sinσ*sinσ)*(-3+4*cos2σm*cos2σm)))
+	return wgs84B * A * (σ - dσ), nil
+}
+
+// Bearing returns the direction to set off in from p to reach q along a great
+// circle, in degrees clockwise from north, [0, 360). the bearing changes along
+// the way, this is only the initial one
+func (p LatLng) Bearing(q LatLng) float64 {
+	φ1, φ2 := rad(p.Lat), rad(q.Lat)
+	dλ := rad(q.Lng - p.Lng)
+	y := math.Sin(dλ) * math.Cos(φ2)
+	x := math.Cos(φ1)*math.Sin(φ2) - math.Sin(φ1)*math.Cos(φ2)*math.Cos(dλ)
+	return math.Mod(deg(math.Atan2(y, x))+360, 360)
+}
+
+// Midpoint returns the point halfway along the great circle from p to q
+func (p LatLng) Midpoint(q LatLng) LatLng {
+	φ1, φ2 := rad(p.Lat), rad(q.Lat)
+	λ1, dλ := rad(p.Lng), rad(q.Lng-p.Lng)
+	bx := math.Cos(φ2) * math.Cos(dλ)
+	by := math.Cos(φ2) * math.Sin(dλ)
+	φm := math.Atan2(math.Sin(φ1)+math.Sin(φ2), math.Hypot(math.Cos(φ1)+bx, by))
+	λm := λ1 + math.Atan2(by, math.Cos(φ1)+bx)
+	return LatLng{deg(φm), normLng(deg(λm))}
+}
+
+// Box is a latitude/longitude rectangle. a box that crosses the 180th
+// meridian has Min.Lng > Max.Lng
+type Box struct {
+	Min, Max LatLng
+}
+
+// Contains reports whether p is inside b, edges included
+func (b Box) Contains(p LatLng) bool {
+	if p.Lat < b.Min.Lat || p.Lat > b.Max.Lat {
+		return false
+	}
+	if b.Min.Lng <= b.Max.Lng {
+		return p.Lng >= b.Min.Lng && p.Lng <= b.Max.Lng
+	}
+	return p.Lng >= b.Min.Lng || p.Lng <= b.Max.Lng // wraps around
+}
+
+func (b Box) String() string {
+	return fmt.Sprintf("[%g,%g .. %g,%g]", b.Min.Lat, b.Min.Lng, b.Max.Lat, b.Max.Lng)
+}
+
+// Bounds returns the smallest box holding every point. it doesn't try to be
+// clever about the 180th meridian, so points either side of it give a box
+// going the long way round
+func Bounds(points ...LatLng) Box {
+	if len(points) == 0 {
+		return Box{}
+	}
+	b := Box{points[0], points[0]}
+	for _, p := range points[1:] {
+		b.Min.Lat, b.Max.Lat = math.Min(b.Min.Lat, p.Lat), math.Max(b.Max.Lat, p.Lat)
+		b.Min.Lng, b.Max.Lng = math.Min(b.Min.Lng, p.Lng), math.Max(b.Max.Lng, p.Lng)
+	}
+	return b
+}
+
+// BoxAround returns a box holding every point within km of p. the box is a
+// little bigger than the circle, so use it to rule points out cheaply and
+// then check the real distance
+func (p LatLng) BoxAround(km float64) Box {
+	ang := km / EarthRadius
+	minLat, maxLat := p.Lat-deg(ang), p.Lat+deg(ang)
+	if minLat <= -90 || maxLat >= 90 {
+		// the circle covers a pole, so every longitude is in
+		return Box{LatLng{math.Max(minLat, -90), -180}, LatLng{math.Min(maxLat, 90), 180}}
+	}
+	dλ := deg(math.Asin(math.Sin(ang) / math.Cos(rad(p.Lat))))
+	if dλ >= 180 {
+		return Box{LatLng{minLat, -180}, LatLng{maxLat, 180}}
+	}
+	return Box{LatLng{minLat, normLng(p.Lng - dλ)}, LatLng{maxLat, normLng(p.Lng + dλ)}}
+}
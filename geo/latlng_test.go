@@ -0,0 +1,153 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// dms turns degrees, minutes and seconds into degrees
+func dms(d, m, s float64) float64 {
+	if d < 0 {
+		return d - m/60 - s/3600
+	}
+	return d + m/60 + s/3600
+}
+
+func TestVincenty(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		p, q LatLng
+		km   float64
+	}{
+		// the worked example from Vincenty's 1975 paper, as used by
+		// Geoscience Australia: 54972.271 m
+		{"Flinders Peak to Buninyong",
+			LatLng{dms(-37, 57, 3.72030), dms(144, 25, 29.52440)},
+			LatLng{dms(-37, 39, 10.15610), dms(143, 55, 35.38390)},
+			54.972271},
+		// a quarter of the equator is a quarter of 2 pi a
+		{"along the equator", LatLng{0, 0}, LatLng{0, 90}, math.Pi / 2 * wgs84A},
+		// and a quarter of a meridian is the ellipse's quarter perimeter
+		{"equator to pole", LatLng{0, 0}, LatLng{90, 0}, 10001.965729},
+		{"same point", LatLng{51.5, -0.1}, LatLng{51.5, -0.1}, 0},
+	} {
+		got, err := tt.p.Vincenty(tt.q)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		// to the millimetre
+		if math.Abs(got-tt.km) > 1e-6 {
+			t.Errorf("%s: got %.6f km, want %.6f", tt.name, got, tt.km)
+		}
+	}
+}
+
+func TestVincentyAntipodal(t *testing.T) {
+	_, err := LatLng{0, 0}.Vincenty(LatLng{0.5, 179.7})
+	if !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("got %v, want ErrNoConvergence", err)
+	}
+}
+
+func TestHaversine(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		p, q LatLng
+		km   float64
+	}{
+		// a degree of latitude on the sphere is 2 pi R / 360
+		{"one degree north", LatLng{10, 20}, LatLng{11, 20}, 2 * math.Pi * EarthRadius / 360},
+		{"half way round", LatLng{0, 0}, LatLng{0, 180}, math.Pi * EarthRadius},
+		{"pole to pole", LatLng{90, 0}, LatLng{-90, 0}, math.Pi * EarthRadius},
+		// Land's End to John o' Groats, the example on movable-type.co.uk:
+		// 968.9 km
+		{"Land's End to John o' Groats",
+			LatLng{dms(50, 3, 59), -dms(5, 42, 53)},
+			LatLng{dms(58, 38, 38), -dms(3, 4, 12)},
+			968.9},
+	} {
+		got := tt.p.Haversine(tt.q)
+		if math.Abs(got-tt.km) > 0.05 {
+			t.Errorf("%s: got %.3f km, want %.3f", tt.name, got, tt.km)
+		}
+		if back := tt.q.Haversine(tt.p); back != got {
+			t.Errorf("%s: %.3f one way, %.3f the other", tt.name, got, back)
+		}
+	}
+}
+
+// the sphere is close to the ellipsoid, haversine is meant to be within 0.5%
+func TestHaversineNearVincenty(t *testing.T) {
+	places := []LatLng{{51.5074, -0.1278}, {40.7128, -74.006}, {-33.8688, 151.2093}, {35.6762, 139.6503}, {-1.2921, 36.8219}}
+	for _, p := range places {
+		for _, q := range places {
+			v, err := p.Vincenty(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if h := p.Haversine(q); math.Abs(h-v) > 0.005*v {
+				t.Errorf("%v to %v: haversine %.1f km, vincenty %.1f", p, q, h, v)
+			}
+		}
+	}
+}
+
+func TestBoxAround(t *testing.T) {
+	p := LatLng{51.5, 179.9} // right by the 180th meridian
+	box := p.BoxAround(100)
+	if box.Min.Lng <= box.Max.Lng {
+		t.Errorf("%v should wrap round", box)
+	}
+	for _, q := range []LatLng{{51.5, -179.5}, {52, 179.9}, {51, 179}} {
+		if p.Haversine(q) <= 100 && !box.Contains(q) {
+			t.Errorf("%v is %.1f km away but not in %v", q, p.Haversine(q), box)
+		}
+	}
+	if box.Contains(LatLng{51.5, 0}) {
+		t.Errorf("%v contains the other side of the world", box)
+	}
+}
+
+const placesCSV = `name, lat, lng
+London, 51.5074, -0.1278
+Paris, 48.8566, 2.3522
+Berlin, 52.52, 13.405
+New York, 40.7128, -74.006
+`
+
+func TestNearest(t *testing.T) {
+	ps, err := LoadCSV(strings.NewReader(placesCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	london, _ := ps.Get("London")
+	var names []string
+	for _, m := range ps.Nearest(london, 3) {
+		names = append(names, m.Name)
+	}
+	if strings.Join(names, ",") != "London,Paris,Berlin" {
+		t.Errorf("Nearest(London, 3) = %v", names)
+	}
+	if n := len(ps.Nearest(london, 10)); n != 4 {
+		t.Errorf("asking for 10 of 4 places gave %d", n)
+	}
+	for _, k := range []int{0, -1} {
+		if ms := ps.Nearest(london, k); ms != nil {
+			t.Errorf("Nearest(London, %d) = %v, want nil", k, ms)
+		}
+	}
+	if ms := ps.Within(london, 400); len(ms) != 2 {
+		t.Errorf("Within 400 km of London = %v, want London and Paris", ms)
+	}
+}
+
+func TestLoadCSVBad(t *testing.T) {
+	for _, s := range []string{"a, 1", "a, x, 2", "a, 91, 0"} {
+		if _, err := LoadCSV(strings.NewReader(s)); err == nil {
+			t.Errorf("%q was accepted", s)
+		}
+	}
+}
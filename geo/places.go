@@ -0,0 +1,192 @@
+package geo
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Place is a named LatLng
+type Place struct {
+	Name string
+	LatLng
+}
+
+// Places is a set of named places you can search by distance. the zero value
+// is empty and ready to use
+type Places struct {
+	places []Place
+	byName map[string]int
+}
+
+// Add adds a place, or moves it if the name is already taken
+func (ps *Places) Add(name string, p LatLng) {
+	if ps.byName == nil {
+		ps.byName = map[string]int{}
+	}
+	if i, ok := ps.byName[name]; ok {
+		ps.places[i].LatLng = p
+		return
+	}
+	ps.byName[name] = len(ps.places)
+	ps.places = append(ps.places, Place{name, p})
+}
+
+// Get looks a place up by name
+func (ps *Places) Get(name string) (LatLng, bool) {
+	i, ok := ps.byName[name]
+	if !ok {
+		return LatLng{}, false
+	}
+	return ps.places[i].LatLng, true
+}
+
+// Len returns how many places there are
+func (ps *Places) Len() int { return len(ps.places) }
+
+// All returns the places in the order they were added
+func (ps *Places) All() []Place {
+	return append([]Place(nil), ps.places...)
+}
+
+// Distance returns the haversine distance in km between two named places
+func (ps *Places) Distance(from, to string) (float64, error) {
+	a, ok := ps.Get(from)
+	if !ok {
+		return 0, fmt.Errorf("geo: no place called %q", from)
+	}
+	b, ok := ps.Get(to)
+	if !ok {
+		return 0, fmt.Errorf("geo: no place called %q", to)
+	}
+	return a.Haversine(b), nil
+}
+
+// Match is a search result
+type Match struct {
+	Place
+	Km float64 // distance from the search point
+}
+
+// Nearest returns the k places closest to p, closest first. k <= 0 gives
+// nothing
+func (ps *Places) Nearest(p LatLng, k int) []Match {
+	if k <= 0 {
+		return nil
+	}
+	ms := make([]Match, len(ps.places))
+	for i, pl := range ps.places {
+		ms[i] = Match{pl, p.Haversine(pl.LatLng)}
+	}
+	sortMatches(ms)
+	return ms[:min(k, len(ms))]
+}
+
+// Within returns every place no more than km from p, closest first
+func (ps *Places) Within(p LatLng, km float64) []Match {
+	box := p.BoxAround(km)
+	var ms []Match
+	for _, pl := range ps.places {
+		// the box check is cheap and rules out most places without any trig
+		if !box.Contains(pl.LatLng) {
+			continue
+		}
+		if d := p.Haversine(pl.LatLng); d <= km {
+			ms = append(ms, Match{pl, d})
+		}
+	}
+	sortMatches(ms)
+	return ms
+}
+
+// closest first, then by name so ties come out the same every time
+func sortMatches(ms []Match) {
+	sort.Slice(ms, func(i, j int) bool {
+		if ms[i].Km != ms[j].Km {
+			return ms[i].Km < ms[j].Km
+		}
+		return ms[i].Name < ms[j].Name
+	})
+}
+
+// LoadCSV reads places from csv with name, lat, lng columns. a header row
+// is skipped if there is one
+func LoadCSV(r io.Reader) (*Places, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+	ps := &Places{}
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return ps, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("geo: reading csv: %w", err)
+		}
+		if line == 1 && strings.EqualFold(rec[0], "name") {
+			continue
+		}
+		lat, err1 := strconv.ParseFloat(rec[1], 64)
+		lng, err2 := strconv.ParseFloat(rec[2], 64)
+		if err := errors.Join(err1, err2); err != nil {
+			return nil, fmt.Errorf("geo: csv line %d: %w", line, err)
+		}
+		if err := ps.add(rec[0], LatLng{lat, lng}); err != nil {
+			return nil, fmt.Errorf("geo: csv line %d: %w", line, err)
+		}
+	}
+}
+
+// just the bits of geojson we need
+type featureCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Geometry struct {
+			Type        string    `json:"type"`
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties map[string]any `json:"properties"`
+	} `json:"features"`
+}
+
+// LoadGeoJSON reads places from a geojson FeatureCollection. every feature
+// has to be a Point with a "name" property. careful, geojson puts longitude
+// first: [lng, lat]
+func LoadGeoJSON(r io.Reader) (*Places, error) {
+	var fc featureCollection
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return nil, fmt.Errorf("geo: reading geojson: %w", err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("geo: geojson is a %q, want a FeatureCollection", fc.Type)
+	}
+	ps := &Places{}
+	for i, f := range fc.Features {
+		if f.Geometry.Type != "Point" || len(f.Geometry.Coordinates) < 2 {
+			return nil, fmt.Errorf("geo: geojson feature %d is not a Point", i)
+		}
+		name, _ := f.Properties["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("geo: geojson feature %d has no name property", i)
+		}
+		c := f.Geometry.Coordinates
+		if err := ps.add(name, LatLng{Lat: c[1], Lng: c[0]}); err != nil {
+			return nil, fmt.Errorf("geo: geojson feature %d: %w", i, err)
+		}
+	}
+	return ps, nil
+}
+
+func (ps *Places) add(name string, p LatLng) error {
+	if !p.Valid() {
+		return fmt.Errorf("%s is at %v, which isn't on the earth", name, p)
+	}
+	ps.Add(name, p)
+	return nil
+}
@@ -0,0 +1,165 @@
+// Package geo is the one shared home for the vertex types the lessons used to
+// define for themselves. Vec2 is a point or vector on a flat plane and comes
+// in int and float64 flavours. LatLng is a position on the earth, which is
+// a different thing entirely and doesn't get vector maths
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Number is what a Vec2 can be made of
+type Number interface {
+	int | float64
+}
+
+// Vec2 is a 2D vector. Vec2[int] is the grid point from the structs lesson,
+// Vec2[float64] is methods.Vertex
+type Vec2[T Number] struct {
+	X, Y T
+}
+
+// Float converts v to float64. always exact for ints up to 2^53
+func (v Vec2[T]) Float() Vec2[float64] {
+	return Vec2[float64]{float64(v.X), float64(v.Y)}
+}
+
+// Round converts v to ints, rounding halves away from zero like math.Round.
+// Round(v.Float()) == v for any Vec2[int] that Float represents exactly
+func Round(v Vec2[float64]) Vec2[int] {
+	return Vec2[int]{int(math.Round(v.X)), int(math.Round(v.Y))}
+}
+
+// method (kinda like an extension function)
+func (v Vec2[T]) Abs() float64 {
+	f := v.Float()
+	return math.Sqrt(f.X*f.X + f.Y*f.Y)
+}
+
+/*
+pointer receiver. ie you can create methods on *T receivers
+Methods with pointer receivers can modify the value to which the receiver points (as Scale does here).
+Since methods often need to modify their receiver, pointer receivers are more common than value receivers.
+changing to v Vec2[T] would have a different result b/c value receivers would operate on a copy of V. not V itself
+*/
+func (v *Vec2[T]) Scale(f T) {
+	v.X = v.X * f
+	v.Y = v.Y * f
+}
+
+// the rest of the methods turn Vec2 into a proper vector. unlike Scale these
+// all have value receivers and return a new Vec2, so they can be chained,
+// eg. v.Sub(u).Normalize(). anything that needs fractions returns a
+// Vec2[float64] whatever T is
+
+// Add returns v + u
+func (v Vec2[T]) Add(u Vec2[T]) Vec2[T] {
+	return Vec2[T]{v.X + u.X, v.Y + u.Y}
+}
+
+// Sub returns v - u
+func (v Vec2[T]) Sub(u Vec2[T]) Vec2[T] {
+	return Vec2[T]{v.X - u.X, v.Y - u.Y}
+}
+
+// Dot returns the dot product. zero means v and u are at right angles
+func (v Vec2[T]) Dot(u Vec2[T]) T {
+	return v.X*u.X + v.Y*u.Y
+}
+
+// Cross returns the z component of the 3D cross product. it's positive when
+// u is counterclockwise from v and negative when clockwise
+func (v Vec2[T]) Cross(u Vec2[T]) T {
+	return v.X*u.Y - v.Y*u.X
+}
+
+// Normalize returns a vector of length 1 pointing the same way as v.
+// the zero vector has no direction so it stays the zero vector
+func (v Vec2[T]) Normalize() Vec2[float64] {
+	l := v.Abs()
+	if l == 0 {
+		return Vec2[float64]{}
+	}
+	f := v.Float()
+	return Vec2[float64]{f.X / l, f.Y / l}
+}
+
+// Angle returns the direction of v in radians, counterclockwise from the
+// positive X axis, in (-Pi, Pi]
+func (v Vec2[T]) Angle() float64 {
+	return math.Atan2(float64(v.Y), float64(v.X))
+}
+
+// AngleTo returns the signed angle in radians to turn v onto u
+func (v Vec2[T]) AngleTo(u Vec2[T]) float64 {
+	return math.Atan2(float64(v.Cross(u)), float64(v.Dot(u)))
+}
+
+// Rotate returns v turned counterclockwise by theta radians
+func (v Vec2[T]) Rotate(theta float64) Vec2[float64] {
+	sin, cos := math.Sincos(theta)
+	f := v.Float()
+	return Vec2[float64]{f.X*cos - f.Y*sin, f.X*sin + f.Y*cos}
+}
+
+// Lerp returns the point t of the way from v to u. t = 0 is v, t = 1 is u
+func (v Vec2[T]) Lerp(u Vec2[T], t float64) Vec2[float64] {
+	a, b := v.Float(), u.Float()
+	return Vec2[float64]{a.X + (b.X-a.X)*t, a.Y + (b.Y-a.Y)*t}
+}
+
+// Distance returns how far apart v and u are
+func (v Vec2[T]) Distance(u Vec2[T]) float64 {
+	return v.Sub(u).Abs()
+}
+
+// ApproxEqual reports whether v and u are within epsilon of each other on both
+// axes. floats pick up rounding errors, so == is rarely what you want after
+// something like Rotate
+func (v Vec2[T]) ApproxEqual(u Vec2[T], epsilon float64) bool {
+	return math.Abs(float64(v.X-u.X)) <= epsilon && math.Abs(float64(v.Y-u.Y)) <= epsilon
+}
+
+// String prints v as (X, Y). same idea as methods.Person's String
+func (v Vec2[T]) String() string {
+	return fmt.Sprintf("(%v, %v)", v.X, v.Y)
+}
+
+// MarshalText and UnmarshalText use the String format. this is also what
+// encoding/json uses when a Vec2 is a map key
+func (v Vec2[T]) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+func (v *Vec2[T]) UnmarshalText(text []byte) error {
+	var u Vec2[T]
+	if _, err := fmt.Sscanf(string(text), "(%v, %v)", &u.X, &u.Y); err != nil {
+		return fmt.Errorf("geo: can't read %q as a %T: %w", text, u, err)
+	}
+	*v = u
+	return nil
+}
+
+// vec2JSON is Vec2 with lower case json names. marshalling it instead of
+// Vec2 also stops MarshalJSON calling itself forever
+type vec2JSON[T Number] struct {
+	X T `json:"x"`
+	Y T `json:"y"`
+}
+
+// MarshalJSON writes v as {"x":3,"y":4}. without it encoding/json would use
+// MarshalText and write a string
+func (v Vec2[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(vec2JSON[T](v))
+}
+
+func (v *Vec2[T]) UnmarshalJSON(data []byte) error {
+	var j vec2JSON[T]
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*v = Vec2[T](j)
+	return nil
+}
@@ -0,0 +1,178 @@
+package geo
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+func TestFloatRoundTrip(t *testing.T) {
+	for _, v := range []Vec2[int]{
+		{0, 0}, {3, -4}, {-1, 1}, {math.MaxInt32, math.MinInt32},
+		{1 << 53, -(1 << 53)}, // the biggest a float64 holds exactly
+	} {
+		if got := Round(v.Float()); got != v {
+			t.Errorf("Round(%v.Float()) = %v", v, got)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	for _, tt := range []struct {
+		in   Vec2[float64]
+		want Vec2[int]
+	}{
+		{Vec2[float64]{0.4, -0.4}, Vec2[int]{0, 0}},
+		{Vec2[float64]{0.5, -0.5}, Vec2[int]{1, -1}},
+		{Vec2[float64]{2.5, -2.5}, Vec2[int]{3, -3}},
+		{Vec2[float64]{1.9999, -7.6}, Vec2[int]{2, -8}},
+	} {
+		if got := Round(tt.in); got != tt.want {
+			t.Errorf("Round(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	ints := []Vec2[int]{{0, 0}, {3, -4}, {math.MaxInt64, math.MinInt64}}
+	for _, v := range ints {
+		text, err := v.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Vec2[int]
+		if err := got.UnmarshalText(text); err != nil {
+			t.Errorf("UnmarshalText(%q): %v", text, err)
+		} else if got != v {
+			t.Errorf("%v came back as %v", v, got)
+		}
+	}
+	floats := []Vec2[float64]{{0, 0}, {0.1, -2.5}, {1e300, -1e-300}, {math.Pi, math.SmallestNonzeroFloat64}}
+	for _, v := range floats {
+		text, err := v.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Vec2[float64]
+		if err := got.UnmarshalText(text); err != nil {
+			t.Errorf("UnmarshalText(%q): %v", text, err)
+		} else if got != v {
+			t.Errorf("%v came back as %v", v, got)
+		}
+	}
+}
+
+func TestUnmarshalTextBad(t *testing.T) {
+	for _, s := range []string{"", "(1, 2", "1, 2", "(a, b)", "(1.5, 2)"} {
+		v := Vec2[int]{7, 7}
+		if err := v.UnmarshalText([]byte(s)); err == nil {
+			t.Errorf("%q was accepted as %v", s, v)
+		} else if v != (Vec2[int]{7, 7}) {
+			t.Errorf("%q: failed but still changed v to %v", s, v)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	v := Vec2[int]{3, 4}
+	b, err := json.Marshal(map[Vec2[int]]Vec2[int]{v: v})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// keys go through MarshalText, values through MarshalJSON
+	if want := `{"(3, 4)":{"x":3,"y":4}}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+	var m map[Vec2[int]]Vec2[int]
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m[v] != v {
+		t.Errorf("round trip gave %v", m)
+	}
+}
+
+// vec makes testing/quick pick sensible vectors. left to itself it uses
+// floats right up to MaxFloat64, and squaring those in Abs gives +Inf
+type vec struct{ V Vec2[float64] }
+
+func (vec) Generate(r *rand.Rand, size int) reflect.Value {
+	f := func() float64 { return (r.Float64()*2 - 1) * float64(size) }
+	return reflect.ValueOf(vec{Vec2[float64]{X: f(), Y: f()}})
+}
+
+// close enough, relative to how big the numbers are
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*max(1, math.Abs(a), math.Abs(b))
+}
+
+func check(t *testing.T, name string, f any) {
+	t.Helper()
+	if err := quick.Check(f, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Errorf("%s: %v", name, err)
+	}
+}
+
+func TestVec2Add(t *testing.T) {
+	check(t, "v+u == u+v", func(v, u vec) bool {
+		return v.V.Add(u.V) == u.V.Add(v.V)
+	})
+	check(t, "v+0 == v", func(v vec) bool {
+		return v.V.Add(Vec2[float64]{}) == v.V
+	})
+	check(t, "(v+u)-u == v", func(v, u vec) bool {
+		return v.V.Add(u.V).Sub(u.V).ApproxEqual(v.V, 1e-9)
+	})
+	check(t, "|v+u| <= |v|+|u|", func(v, u vec) bool {
+		return v.V.Add(u.V).Abs() <= v.V.Abs()+u.V.Abs()+1e-9
+	})
+}
+
+func TestVec2Scale(t *testing.T) {
+	check(t, "|v*f| == |f||v|", func(v vec, f float64) bool {
+		f = math.Mod(f, 100)
+		s := v.V
+		s.Scale(f)
+		return near(s.Abs(), math.Abs(f)*v.V.Abs())
+	})
+	check(t, "v*1 == v", func(v vec) bool {
+		s := v.V
+		s.Scale(1)
+		return s == v.V
+	})
+	check(t, "(v+u)*f == v*f + u*f", func(v, u vec, f float64) bool {
+		f = math.Mod(f, 100)
+		sum := v.V.Add(u.V)
+		sum.Scale(f)
+		a, b := v.V, u.V
+		a.Scale(f)
+		b.Scale(f)
+		return sum.ApproxEqual(a.Add(b), 1e-6)
+	})
+}
+
+func TestVec2Abs(t *testing.T) {
+	check(t, "|v| >= 0", func(v vec) bool {
+		return v.V.Abs() >= 0
+	})
+	check(t, "|v| == |-v|", func(v vec) bool {
+		n := v.V
+		n.Scale(-1)
+		return v.V.Abs() == n.Abs()
+	})
+	check(t, "rotating keeps |v|", func(v vec, theta float64) bool {
+		return near(v.V.Rotate(theta).Abs(), v.V.Abs())
+	})
+	check(t, "|Normalize(v)| == 1", func(v vec) bool {
+		return v.V == Vec2[float64]{} || near(v.V.Normalize().Abs(), 1)
+	})
+	if a := (Vec2[float64]{X: 3, Y: 4}).Abs(); a != 5 {
+		t.Errorf("|(3, 4)| = %g, want 5", a)
+	}
+	if a := (Vec2[float64]{}).Abs(); a != 0 {
+		t.Errorf("|(0, 0)| = %g, want 0", a)
+	}
+}
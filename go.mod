@@ -0,0 +1,3 @@
+module github.com/DaveM7788/tourOfGo
+
+go 1.24
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/DaveM7788/tourOfGo/growth"
+)
+
+// growthCmd shows every time append had to grow a slice and what that cost
+// compared to making it the right size to begin with
+func growthCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("growth", flag.ContinueOnError)
+	n := fs.Int("n", 1000, "how many elements to append")
+	size := fs.Int("size", 8, fmt.Sprintf("bytes per element, one of %v", growth.Sizes()))
+	format := fs.String("format", "table", "table or csv")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	r, err := growth.Explore(*n, *size)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "table":
+		return growthTable(w, r)
+	case "csv":
+		return growthCSV(w, r)
+	}
+	return fmt.Errorf("unknown format %q. use table or csv", *format)
+}
+
+func growthTable(w io.Writer, r growth.Result) error {
+	fmt.Fprintf(w, "appending %d elements of %d bytes onto a nil slice\n\n", r.N, r.Size)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "len\told cap\tcap\tfactor\treallocs\tbytes\t")
+	for _, s := range r.Steps {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%d\t%d\t\n", s.Len, s.OldCap, s.Cap, factor(s), s.Reallocs, s.Bytes)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tarrays\tbytes allocated")
+	fmt.Fprintf(tw, "append onto nil\t%d\t%d\n", r.Reallocs(), r.Bytes)
+	fmt.Fprintf(tw, "make([]T, 0, %d)\t%d\t%d\n", r.N, r.Presized.Reallocs, r.Presized.Bytes)
+	return tw.Flush()
+}
+
+// growthCSV writes one row per array, for both ways of building the slice
+func growthCSV(w io.Writer, r growth.Result) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"strategy", "size", "len", "old_cap", "cap", "factor", "reallocs", "bytes"})
+	row := func(strategy string, s growth.Step) {
+		cw.Write([]string{
+			strategy, strconv.Itoa(r.Size), strconv.Itoa(s.Len), strconv.Itoa(s.OldCap),
+			strconv.Itoa(s.Cap), factor(s), strconv.Itoa(s.Reallocs), strconv.Itoa(s.Bytes),
+		})
+	}
+	for _, s := range r.Steps {
+		row("append", s)
+	}
+	if r.Presized.Reallocs > 0 {
+		row("presized", r.Presized)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func factor(s growth.Step) string {
+	if s.OldCap == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(s.Factor(), 'f', 2, 64)
+}
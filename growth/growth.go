@@ -0,0 +1,174 @@
+// Package growth watches append grow a slice one element at a time.
+//
+// when append runs out of cap it asks the runtime for a bigger array. the
+// rule (runtime.growslice) is roughly
+//
+//   - if you need more than double the old cap, you get exactly what you need
+//   - under 256 elements the cap doubles
+//   - after that it grows by (cap + 3*256) / 4 each time, which slides from
+//     2x down towards 1.25x as the slice gets big
+//
+// and then the size is rounded up to one of the allocator's size classes, so
+// the cap you actually see depends on how big each element is. that's why
+// sliceAppend ends on cap=6 and not 5
+package growth
+
+import (
+	"fmt"
+	"slices"
+	"unsafe"
+)
+
+// Step is a point where append had to move to a bigger array
+type Step struct {
+	Len      int // len after the append that grew it
+	OldCap   int
+	Cap      int
+	Reallocs int // how many times it has grown so far, including this one
+	Bytes    int // size of the new array
+}
+
+// Factor is how much bigger the new array is than the old one. 0 for the
+// first allocation
+func (s Step) Factor() float64 {
+	if s.OldCap == 0 {
+		return 0
+	}
+	return float64(s.Cap) / float64(s.OldCap)
+}
+
+// Result is what happened appending N elements of one size
+type Result struct {
+	N, Size int
+	Steps   []Step
+
+	// Presized is the one allocation make([]T, 0, n) does up front. for
+	// n == 0 there isn't one, and Reallocs is 0
+	Presized Step
+
+	// Bytes is every array append allocated added up. the old ones are
+	// garbage as soon as they are copied out of
+	Bytes int
+}
+
+// Reallocs is how many arrays append went through
+func (r Result) Reallocs() int {
+	return len(r.Steps)
+}
+
+// the element sizes we can try. append only knows the size of a type at
+// compile time, so each one needs its own type
+type (
+	b1   [1]byte
+	b2   [2]byte
+	b4   [4]byte
+	b8   [8]byte
+	b12  [12]byte
+	b16  [16]byte
+	b24  [24]byte
+	b32  [32]byte
+	b48  [48]byte
+	b64  [64]byte
+	b128 [128]byte
+)
+
+var explorers = map[int]func(n int) Result{
+	1:   explore[b1],
+	2:   explore[b2],
+	4:   explore[b4],
+	8:   explore[b8],
+	12:  explore[b12],
+	16:  explore[b16],
+	24:  explore[b24],
+	32:  explore[b32],
+	48:  explore[b48],
+	64:  explore[b64],
+	128: explore[b128],
+}
+
+// Sizes lists the element sizes Explore accepts
+func Sizes() []int {
+	var s []int
+	for k := range explorers {
+		s = append(s, k)
+	}
+	slices.Sort(s)
+	return s
+}
+
+// Explore appends n elements of size bytes each onto a nil slice and records
+// every time the cap changed
+func Explore(n, size int) (Result, error) {
+	if n < 0 {
+		return Result{}, fmt.Errorf("growth: can't append %d elements", n)
+	}
+	f, ok := explorers[size]
+	if !ok {
+		return Result{}, fmt.Errorf("growth: no %d byte element type. try one of %v", size, Sizes())
+	}
+	return f(n), nil
+}
+
+// Of records the cap changes for any element type
+func Of[T any](n int) []Step {
+	size := int(unsafe.Sizeof(*new(T)))
+	var steps []Step
+	var s []T
+	var zero T
+	for range n {
+		old := cap(s)
+		s = append(s, zero)
+		if cap(s) != old {
+			steps = append(steps, Step{
+				Len:      len(s),
+				OldCap:   old,
+				Cap:      cap(s),
+				Reallocs: len(steps) + 1,
+				Bytes:    cap(s) * size,
+			})
+		}
+	}
+	// without this newer compilers keep the first few elements in a buffer
+	// on the stack, and the caps we see aren't the ones a real slice gets
+	sink = unsafe.Pointer(unsafe.SliceData(s))
+	return steps
+}
+
+func explore[T any](n int) Result {
+	size := int(unsafe.Sizeof(*new(T)))
+	r := Result{N: n, Size: size, Steps: Of[T](n)}
+	if n > 0 {
+		// make([]T, 0, 0) points at a shared zero sized spot, nothing is allocated
+		r.Presized = Step{Cap: n, Reallocs: 1, Bytes: n * size}
+	}
+	for _, s := range r.Steps {
+		r.Bytes += s.Bytes
+	}
+	return r
+}
+
+// sink stops the compiler noticing the slices never leave the function and
+// putting them on the stack, which would hide the allocations. it's a plain
+// pointer because putting a slice in an interface allocates too
+var sink unsafe.Pointer
+
+// Grown appends n zero values onto a nil slice, the way sliceAppend does
+func Grown[T any](n int) {
+	var s []T
+	var zero T
+	for range n {
+		s = append(s, zero)
+	}
+	sink = unsafe.Pointer(unsafe.SliceData(s))
+}
+
+// Presized makes room for all n first, like make([]int, 0, 5) in slicesMake,
+// so append never has to copy
+func Presized[T any](n int) {
+	s := make([]T, 0, n)
+	var zero T
+	for range n {
+		s = append(s, zero)
+	}
+	sink = unsafe.Pointer(unsafe.SliceData(s))
+}
@@ -0,0 +1,86 @@
+package growth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExplore(t *testing.T) {
+	r, err := Explore(5, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sliceAppend: 1, 2, 4 then 8
+	var caps []int
+	for _, s := range r.Steps {
+		caps = append(caps, s.Cap)
+	}
+	if fmt.Sprint(caps) != "[1 2 4 8]" {
+		t.Errorf("caps %v, want [1 2 4 8]", caps)
+	}
+	if r.Bytes != (1+2+4+8)*8 {
+		t.Errorf("Bytes = %d, want %d", r.Bytes, (1+2+4+8)*8)
+	}
+	if r.Presized != (Step{Cap: 5, Reallocs: 1, Bytes: 40}) {
+		t.Errorf("Presized = %+v", r.Presized)
+	}
+}
+
+func TestExploreNothing(t *testing.T) {
+	r, err := Explore(0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Reallocs() != 0 || r.Presized.Reallocs != 0 || r.Presized.Bytes != 0 {
+		t.Errorf("appending nothing allocated: %+v", r)
+	}
+}
+
+func TestExploreBadInput(t *testing.T) {
+	if _, err := Explore(-1, 8); err == nil {
+		t.Error("n = -1 was accepted")
+	}
+	if _, err := Explore(10, 3); err == nil {
+		t.Error("size 3 was accepted")
+	}
+}
+
+// the steps Explore reports should be the allocations the runtime really
+// does. AllocsPerRun counts them
+func TestAllocs(t *testing.T) {
+	for _, n := range []int{0, 1, 5, 100, 1000} {
+		r, _ := Explore(n, 8)
+		grown := testing.AllocsPerRun(100, func() { Grown[b8](n) })
+		if int(grown) != r.Reallocs() {
+			t.Errorf("n=%d: append onto nil made %g allocations, Explore says %d", n, grown, r.Reallocs())
+		}
+		presized := testing.AllocsPerRun(100, func() { Presized[b8](n) })
+		if int(presized) != r.Presized.Reallocs {
+			t.Errorf("n=%d: make made %g allocations, Explore says %d", n, presized, r.Presized.Reallocs)
+		}
+	}
+}
+
+var sizes = []int{10, 1000, 100000}
+
+func BenchmarkGrown(b *testing.B) {
+	for _, n := range sizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				Grown[b8](n)
+			}
+		})
+	}
+}
+
+func BenchmarkPresized(b *testing.B) {
+	for _, n := range sizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				Presized[b8](n)
+			}
+		})
+	}
+}
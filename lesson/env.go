@@ -0,0 +1,41 @@
+package lesson
+
+import (
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/clock"
+)
+
+// Env is everything a lesson might depend on from the outside world.
+// most lessons only need Out. the ones that look at the time or roll dice
+// take the whole Env so they can be replayed with a fake clock and a fixed seed
+type Env struct {
+	Out   io.Writer
+	Clock clock.Clock
+	Rand  *rand.Rand
+
+	// Verbose asks lessons to show their working, eg. the slices lessons draw
+	// the arrays behind their slices. tour run --verbose turns it on
+	Verbose bool
+}
+
+// RealEnv writes to w and uses the wall clock and a randomly seeded source
+func RealEnv(w io.Writer) *Env {
+	return &Env{
+		Out:   w,
+		Clock: clock.Real,
+		Rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// FakeEnv writes to w with a fake clock starting at now and a fixed seed.
+// two runs with the same arguments print the same thing
+func FakeEnv(w io.Writer, now time.Time, seed int64) *Env {
+	return &Env{
+		Out:   w,
+		Clock: clock.NewFake(now),
+		Rand:  rand.New(rand.NewSource(seed)),
+	}
+}
@@ -0,0 +1,97 @@
+package lesson
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// golden files are just the expected output of a lesson, with two escapes for
+// the bits that change from run to run:
+//
+//	{{*}}    anywhere in a line matches any text, eg. "the time is {{*}}"
+//	{{...}}  on a line by itself matches any number of lines, even none
+const (
+	AnyText  = "{{*}}"
+	AnyLines = "{{...}}"
+)
+
+// Match compares a lesson's output against its golden file. it returns nil if
+// they match, otherwise an error describing the first line that differs.
+// if unordered is set the output lines are sorted before comparing
+func Match(got, want string, unordered bool) error {
+	gl, wl := lines(got), lines(want)
+	if unordered {
+		sort.Strings(gl)
+	}
+	if matchLines(wl, gl) {
+		return nil
+	}
+
+	// walk forward until the first line that can't match. this is only for the
+	// error message so it doesn't have to understand {{...}} perfectly
+	i := 0
+	for i < len(wl) && i < len(gl) && wl[i] != AnyLines && matchLine(wl[i], gl[i]) {
+		i++
+	}
+	var w, g string
+	switch {
+	case i < len(wl):
+		w = fmt.Sprintf("%q", wl[i])
+	default:
+		w = "end of output"
+	}
+	switch {
+	case i < len(gl):
+		g = fmt.Sprintf("%q", gl[i])
+	default:
+		g = "end of output"
+	}
+	return fmt.Errorf("line %d:\n\twant %s\n\t got %s", i+1, w, g)
+}
+
+func lines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// matchLines is a tiny backtracking matcher. lesson output is a few dozen
+// lines at most so there is no need to be clever
+func matchLines(want, got []string) bool {
+	if len(want) == 0 {
+		return len(got) == 0
+	}
+	if want[0] == AnyLines {
+		for skip := 0; skip <= len(got); skip++ {
+			if matchLines(want[1:], got[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+	return len(got) > 0 && matchLine(want[0], got[0]) && matchLines(want[1:], got[1:])
+}
+
+// matchLine matches one line where {{*}} stands for any text
+func matchLine(want, got string) bool {
+	parts := strings.Split(want, AnyText)
+	if len(parts) == 1 {
+		return want == got
+	}
+	if !strings.HasPrefix(got, parts[0]) {
+		return false
+	}
+	got = got[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, p := range parts[1 : len(parts)-1] {
+		i := strings.Index(got, p)
+		if i < 0 {
+			return false
+		}
+		got = got[i+len(p):]
+	}
+	return strings.HasSuffix(got, last)
+}
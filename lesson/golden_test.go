@@ -0,0 +1,70 @@
+package lesson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchLine(t *testing.T) {
+	for _, tt := range []struct {
+		want, got string
+		ok        bool
+	}{
+		{"hello", "hello", true},
+		{"hello", "hello ", false},
+		{"", "", true},
+		{"the time is {{*}}", "the time is 23:00", true},
+		{"the time is {{*}}", "the time is ", true},
+		{"the time is {{*}}", "the time was 23:00", false},
+		{"{{*}} seconds", "1.5 seconds", true},
+		{"{{*}} seconds", "1.5 minutes", false},
+		{"{{*}}", "", true},
+		{"{{*}}", "anything at all", true},
+		{"a{{*}}b{{*}}c", "a1b2c", true},
+		{"a{{*}}b{{*}}c", "abc", true},
+		{"a{{*}}b{{*}}c", "a1c", false},
+		// the text between two masks can't also be used for the end
+		{"{{*}}x{{*}}x", "x", false},
+		{"{{*}}x{{*}}x", "xx", true},
+		{"a{{*}}a", "a", false},
+	} {
+		if got := matchLine(tt.want, tt.got); got != tt.ok {
+			t.Errorf("matchLine(%q, %q) = %v, want %v", tt.want, tt.got, got, tt.ok)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		got, want string
+		unordered bool
+		err       string // "" for a match, otherwise the start of the error
+	}{
+		{"same", "a\nb\n", "a\nb\n", false, ""},
+		{"no final newline", "a\nb", "a\nb\n", false, ""},
+		{"empty", "", "", false, ""},
+		{"wrong line", "a\nx\n", "a\nb\n", false, "line 2:\n\twant \"b\"\n\t got \"x\""},
+		{"too short", "a\n", "a\nb\n", false, "line 2:\n\twant \"b\"\n\t got end of output"},
+		{"too long", "a\nb\n", "a\n", false, "line 2:\n\twant end of output\n\t got \"b\""},
+		{"any lines in the middle", "a\n1\n2\n3\nb\n", "a\n{{...}}\nb\n", false, ""},
+		{"any lines can be none", "a\nb\n", "a\n{{...}}\nb\n", false, ""},
+		{"any lines at the end", "a\nb\nc\n", "a\n{{...}}\n", false, ""},
+		{"any lines still needs the rest", "a\nb\n", "a\n{{...}}\nc\n", false, "line 2:"},
+		{"any lines then any text", "x\ny 1\nz\n", "{{...}}\ny {{*}}\nz\n", false, ""},
+		{"order matters", "b\na\n", "a\nb\n", false, "line 1:"},
+		// unordered sorts the output, the golden file is written sorted
+		{"unordered", "world\nhello\nworld\nhello\n", "hello\nhello\nworld\nworld\n", true, ""},
+		{"unordered missing a line", "world\nhello\nhello\n", "hello\nhello\nworld\nworld\n", true, "line 4:"},
+	} {
+		err := Match(tt.got, tt.want, tt.unordered)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.err != "" && err == nil:
+			t.Errorf("%s: matched, want %q", tt.name, tt.err)
+		case tt.err != "" && !strings.HasPrefix(err.Error(), tt.err):
+			t.Errorf("%s: got %q, want %q", tt.name, err, tt.err)
+		}
+	}
+}
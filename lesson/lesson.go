@@ -0,0 +1,95 @@
+// Package lesson is the registry every tour lesson adds itself to.
+// lesson packages call Register from an init func and the tour command
+// looks them up by name or topic. kinda like database/sql drivers
+package lesson
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Lesson is a single runnable example from the tour
+type Lesson struct {
+	Name        string // what you type after `tour run`. the function name
+	Topic       string // basics, point, methods, concurrency...
+	Description string // one line for `tour list`
+	Run         func(w io.Writer)
+
+	// RunEnv is used instead of Run by lessons that need a clock or random
+	// numbers. set one or the other
+	RunEnv func(env *Env)
+
+	// Unordered is for lessons whose lines come out in a different order each
+	// run, like goroutines racing to print. tour verify sorts the output
+	// before comparing, so the golden file has to be sorted too
+	Unordered bool
+}
+
+// Exec runs the lesson in env
+func (l Lesson) Exec(env *Env) {
+	if l.RunEnv != nil {
+		l.RunEnv(env)
+		return
+	}
+	l.Run(env.Out)
+}
+
+var registry = map[string]Lesson{}
+
+// order lessons were registered in. maps don't keep any order
+var names []string
+
+// Register adds l to the registry. it panics on a duplicate or incomplete
+// lesson since that is always a programming mistake, same as http.Handle
+func Register(l Lesson) {
+	if l.Name == "" || l.Topic == "" || (l.Run == nil) == (l.RunEnv == nil) {
+		panic(fmt.Sprintf("lesson: Register called with incomplete lesson %+v", l))
+	}
+	if _, dup := registry[l.Name]; dup {
+		panic("lesson: Register called twice for lesson " + l.Name)
+	}
+	registry[l.Name] = l
+	names = append(names, l.Name)
+}
+
+// Lookup finds a lesson by name
+func Lookup(name string) (Lesson, bool) {
+	l, ok := registry[name]
+	return l, ok
+}
+
+// All returns every lesson sorted by topic. lessons within a topic stay in the
+// order they were registered, which is the order they appear in the source
+func All() []Lesson {
+	all := make([]Lesson, 0, len(names))
+	for _, n := range names {
+		all = append(all, registry[n])
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Topic < all[j].Topic
+	})
+	return all
+}
+
+// ByTopic returns the lessons for one topic, or nil if there is no such topic
+func ByTopic(topic string) []Lesson {
+	var ls []Lesson
+	for _, l := range All() {
+		if l.Topic == topic {
+			ls = append(ls, l)
+		}
+	}
+	return ls
+}
+
+// Topics returns the distinct topics in sorted order
+func Topics() []string {
+	var topics []string
+	for _, l := range All() {
+		if len(topics) == 0 || topics[len(topics)-1] != l.Topic {
+			topics = append(topics, l.Topic)
+		}
+	}
+	return topics
+}
@@ -0,0 +1,207 @@
+// Package list is the generic linked list from the methods lesson, finished.
+// it is doubly linked so both ends are O(1). the zero value is an empty list
+// ready to use, like bytes.Buffer
+package list
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// List holds values of any type. don't copy a non-empty List, share a *List
+type List[T any] struct {
+	front, back *node[T]
+	len         int
+}
+
+type node[T any] struct {
+	next, prev *node[T]
+	val        T
+}
+
+// New returns a list holding vals in order
+func New[T any](vals ...T) *List[T] {
+	return FromSlice(vals)
+}
+
+// FromSlice returns a list holding the elements of s in order. s isn't kept
+func FromSlice[T any](s []T) *List[T] {
+	l := &List[T]{}
+	for _, v := range s {
+		l.Push(v)
+	}
+	return l
+}
+
+// Len returns the number of elements. a nil *List is empty
+func (l *List[T]) Len() int {
+	if l == nil {
+		return 0
+	}
+	return l.len
+}
+
+// Push adds v to the back of the list
+func (l *List[T]) Push(v T) {
+	n := &node[T]{val: v, prev: l.back}
+	if l.back == nil {
+		l.front = n
+	} else {
+		l.back.next = n
+	}
+	l.back = n
+	l.len++
+}
+
+// PushFront adds v to the front of the list
+func (l *List[T]) PushFront(v T) {
+	n := &node[T]{val: v, next: l.front}
+	if l.front == nil {
+		l.back = n
+	} else {
+		l.front.prev = n
+	}
+	l.front = n
+	l.len++
+}
+
+// Pop removes and returns the value at the back. ok is false if the list is
+// empty. Push and Pop together make a stack
+func (l *List[T]) Pop() (v T, ok bool) {
+	if l.Len() == 0 {
+		return v, false
+	}
+	n := l.back
+	l.back = n.prev
+	if l.back == nil {
+		l.front = nil
+	} else {
+		l.back.next = nil
+	}
+	l.len--
+	return n.val, true
+}
+
+// PopFront removes and returns the value at the front. Push and PopFront
+// together make a queue
+func (l *List[T]) PopFront() (v T, ok bool) {
+	if l.Len() == 0 {
+		return v, false
+	}
+	n := l.front
+	l.front = n.next
+	if l.front == nil {
+		l.back = nil
+	} else {
+		l.front.prev = nil
+	}
+	l.len--
+	return n.val, true
+}
+
+// Front returns the first value without removing it
+func (l *List[T]) Front() (v T, ok bool) {
+	if l.Len() == 0 {
+		return v, false
+	}
+	return l.front.val, true
+}
+
+// Back returns the last value without removing it
+func (l *List[T]) Back() (v T, ok bool) {
+	if l.Len() == 0 {
+		return v, false
+	}
+	return l.back.val, true
+}
+
+// Reverse reverses the list in place
+func (l *List[T]) Reverse() {
+	if l.Len() == 0 {
+		return
+	}
+	for n := l.front; n != nil; n = n.prev { // prev is the old next after the swap
+		n.next, n.prev = n.prev, n.next
+	}
+	l.front, l.back = l.back, l.front
+}
+
+// All returns an iterator over the values front to back, for use with
+// range over func:
+//
+//	for v := range l.All() {
+//		fmt.Println(v)
+//	}
+//
+// don't change the list while ranging over it
+func (l *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if l == nil {
+			return
+		}
+		for n := l.front; n != nil; n = n.next {
+			if !yield(n.val) {
+				return
+			}
+		}
+	}
+}
+
+// Backward is like All but goes back to front
+func (l *List[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if l == nil {
+			return
+		}
+		for n := l.back; n != nil; n = n.prev {
+			if !yield(n.val) {
+				return
+			}
+		}
+	}
+}
+
+// Slice copies the values into a new slice, front to back
+func (l *List[T]) Slice() []T {
+	s := make([]T, 0, l.Len())
+	for v := range l.All() {
+		s = append(s, v)
+	}
+	return s
+}
+
+// FindFunc returns the position of the first value f is true for, or -1
+func (l *List[T]) FindFunc(f func(T) bool) int {
+	i := 0
+	for v := range l.All() {
+		if f(v) {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// Find returns the position of the first x in l, or -1. it's a function not a
+// method because methods can't add the comparable constraint to T. same idea
+// as Index in the methods lesson
+func Find[T comparable](l *List[T], x T) int {
+	return l.FindFunc(func(v T) bool { return v == x })
+}
+
+// String prints the list like fmt prints a slice, eg. [1 2 3]
+func (l *List[T]) String() string {
+	var b strings.Builder
+	b.WriteByte('[')
+	i := 0
+	for v := range l.All() {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprint(&b, v)
+		i++
+	}
+	b.WriteByte(']')
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package list
+
+import (
+	"slices"
+	"testing"
+)
+
+// check walks l both ways and makes sure the links agree with want
+func check[T comparable](t *testing.T, l *List[T], want []T) {
+	t.Helper()
+	if got := l.Slice(); !slices.Equal(got, want) {
+		t.Errorf("forwards %v, want %v", got, want)
+	}
+	back := slices.Collect(l.Backward())
+	slices.Reverse(back)
+	if !slices.Equal(back, want) {
+		t.Errorf("backwards gives %v reversed, want %v", back, want)
+	}
+	if l.Len() != len(want) {
+		t.Errorf("Len = %d, want %d", l.Len(), len(want))
+	}
+}
+
+func TestReverse(t *testing.T) {
+	for _, tt := range []struct {
+		in, want []int
+	}{
+		{nil, []int{}},
+		{[]int{1}, []int{1}},
+		{[]int{1, 2}, []int{2, 1}},
+		{[]int{1, 2, 3}, []int{3, 2, 1}},
+		{[]int{1, 2, 3, 4, 5, 6}, []int{6, 5, 4, 3, 2, 1}},
+	} {
+		l := FromSlice(tt.in)
+		l.Reverse()
+		check(t, l, tt.want)
+		// and back again
+		l.Reverse()
+		check(t, l, append([]int{}, tt.in...))
+	}
+}
+
+func TestReverseThenEdit(t *testing.T) {
+	// the ends have to be right after a reverse, not just the order
+	l := New(1, 2, 3)
+	l.Reverse()
+	l.Push(0)
+	l.PushFront(4)
+	check(t, l, []int{4, 3, 2, 1, 0})
+	if v, _ := l.PopFront(); v != 4 {
+		t.Errorf("PopFront = %d, want 4", v)
+	}
+	if v, _ := l.Pop(); v != 0 {
+		t.Errorf("Pop = %d, want 0", v)
+	}
+	check(t, l, []int{3, 2, 1})
+}
+
+func TestZeroAndNil(t *testing.T) {
+	var l List[string]
+	check(t, &l, []string{})
+	if _, ok := l.Pop(); ok {
+		t.Error("Pop on an empty list")
+	}
+	if _, ok := l.PopFront(); ok {
+		t.Error("PopFront on an empty list")
+	}
+	if _, ok := l.Front(); ok {
+		t.Error("Front on an empty list")
+	}
+	l.Reverse()
+	l.Push("a")
+	check(t, &l, []string{"a"})
+
+	var nl *List[int]
+	if nl.Len() != 0 || len(nl.Slice()) != 0 || nl.String() != "[]" {
+		t.Error("a nil list isn't empty")
+	}
+}
+
+func TestStackAndQueue(t *testing.T) {
+	l := New[int]()
+	for i := range 4 {
+		l.Push(i)
+	}
+	// stack
+	if v, _ := l.Pop(); v != 3 {
+		t.Errorf("Pop = %d, want 3", v)
+	}
+	// queue
+	if v, _ := l.PopFront(); v != 0 {
+		t.Errorf("PopFront = %d, want 0", v)
+	}
+	check(t, l, []int{1, 2})
+	l.Pop()
+	l.Pop()
+	check(t, l, []int{})
+	// emptied out, the ends have to be reset too
+	l.PushFront(9)
+	check(t, l, []int{9})
+}
+
+func TestFind(t *testing.T) {
+	l := New("a", "b", "c", "b")
+	for _, tt := range []struct {
+		x    string
+		want int
+	}{{"a", 0}, {"b", 1}, {"c", 2}, {"z", -1}} {
+		if got := Find(l, tt.x); got != tt.want {
+			t.Errorf("Find(%q) = %d, want %d", tt.x, got, tt.want)
+		}
+	}
+	if got := Find(New[string](), "a"); got != -1 {
+		t.Errorf("Find in empty = %d", got)
+	}
+}
+
+func TestAllStopsEarly(t *testing.T) {
+	var seen []int
+	for v := range New(1, 2, 3, 4).All() {
+		if v == 3 {
+			break
+		}
+		seen = append(seen, v)
+	}
+	if !slices.Equal(seen, []int{1, 2}) {
+		t.Errorf("saw %v", seen)
+	}
+}
+
+func TestString(t *testing.T) {
+	if s := New(1, 2, 3).String(); s != "[1 2 3]" {
+		t.Errorf("String = %q", s)
+	}
+}
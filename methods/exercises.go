@@ -0,0 +1,103 @@
+package methods
+
+import "github.com/DaveM7788/tourOfGo/exercise"
+
+func init() {
+	exercise.Register(exercise.Exercise{
+		Name:        "abs",
+		Lesson:      "methodsExt",
+		Description: "the Abs method on Vertex",
+		Stub: `package scratch
+
+type Vertex struct {
+	X, Y float64
+}
+
+// Abs returns the length of v, ie. its distance from the origin
+func (v Vertex) Abs() float64 {
+	// TODO
+	return 0
+}
+`,
+		Tests: `package scratch
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestAbs(t *testing.T) {
+	for _, tc := range []struct {
+		v    Vertex
+		want float64
+	}{
+		{Vertex{3, 4}, 5},
+		{Vertex{0, 0}, 0},
+		{Vertex{-5, 12}, 13},
+		{Vertex{30, 40}, 50},
+		{Vertex{1, 1}, math.Sqrt2},
+	} {
+		t.Run(fmt.Sprint(tc.v), func(t *testing.T) {
+			if got := tc.v.Abs(); math.Abs(got-tc.want) > 1e-9 {
+				t.Errorf("%v.Abs() = %v. want %v", tc.v, got, tc.want)
+			}
+		})
+	}
+}
+`,
+	})
+
+	exercise.Register(exercise.Exercise{
+		Name:        "index",
+		Lesson:      "genericTypeParams",
+		Description: "a generic Index for any comparable type",
+		Stub: `package scratch
+
+// Index returns the index of the first x in s, or -1 if s has no x.
+// it should work for a slice of any comparable type
+func Index[T comparable](s []T, x T) int {
+	// TODO
+	return -1
+}
+`,
+		Tests: `package scratch
+
+import "testing"
+
+func TestIndex(t *testing.T) {
+	t.Run("ints", func(t *testing.T) {
+		if got := Index([]int{10, 20, 15, -10}, 15); got != 2 {
+			t.Errorf("Index(ints, 15) = %d. want 2", got)
+		}
+	})
+	t.Run("strings", func(t *testing.T) {
+		if got := Index([]string{"foo", "bar", "baz"}, "baz"); got != 2 {
+			t.Errorf("Index(strings, baz) = %d. want 2", got)
+		}
+	})
+	t.Run("missing", func(t *testing.T) {
+		if got := Index([]string{"foo", "bar", "baz"}, "hello"); got != -1 {
+			t.Errorf("Index(strings, hello) = %d. want -1", got)
+		}
+	})
+	t.Run("first of duplicates", func(t *testing.T) {
+		if got := Index([]int{1, 2, 1, 2}, 2); got != 1 {
+			t.Errorf("Index(1 2 1 2, 2) = %d. want 1", got)
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		if got := Index[int](nil, 0); got != -1 {
+			t.Errorf("Index(nil, 0) = %d. want -1", got)
+		}
+	})
+	t.Run("structs", func(t *testing.T) {
+		type p struct{ x, y int }
+		if got := Index([]p{{1, 2}, {3, 4}}, p{3, 4}); got != 1 {
+			t.Errorf("Index(structs, {3 4}) = %d. want 1", got)
+		}
+	})
+}
+`,
+	})
+}
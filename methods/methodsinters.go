@@ -1,57 +1,78 @@
-package main
+package methods
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
+	"image/color"
 	"io"
 	"math"
 	"strconv"
 	"strings"
+
+	"github.com/DaveM7788/tourOfGo/errs"
+	"github.com/DaveM7788/tourOfGo/geo"
+	"github.com/DaveM7788/tourOfGo/lesson"
+	"github.com/DaveM7788/tourOfGo/list"
+	"github.com/DaveM7788/tourOfGo/numeric"
+	"github.com/DaveM7788/tourOfGo/pic"
+	"github.com/DaveM7788/tourOfGo/readers"
+	"github.com/DaveM7788/tourOfGo/sliceutil"
 )
 
-type Vertex struct {
-	X, Y float64
-}
+// Vertex used to be its own struct here. it's now the float64 version of the
+// shared geo.Vec2, and = makes this an alias, ie. another name for the exact
+// same type rather than a new one
+type Vertex = geo.Vec2[float64]
 
 // you can only create methods for types defined in the same package
 // you cannot create methdos for built in types. so not like kotlin extension functions then
+// that is why Abs and Scale now live in geo/vec2.go next to Vec2
+
+func init() {
+	for _, l := range []lesson.Lesson{
+		{Name: "methodsExt", Description: "value and pointer receivers", Run: methodsExt},
+		{Name: "vertexMath", Description: "Vertex as a 2D vector", Run: vertexMath},
+		{Name: "interfaceEx", Description: "implicitly implemented interfaces", Run: interfaceEx},
+		{Name: "emptyInterface", Description: "interface{} holds anything", Run: emptyInterface},
+		{Name: "typeAssertion", Description: "i.(T) and the comma ok form", Run: typeAssertion},
+		{Name: "typeSwitch", Description: "switch on a type", Run: typeSwitch},
+		{Name: "stringers", Description: "fmt.Stringer", Run: stringers},
+		{Name: "errorsErr", Description: "the error interface", Run: errorsErr},
+		{Name: "errorsSqrt", Description: "a custom error type from Sqrt", Run: errorsSqrt},
+		{Name: "readersRead", Description: "io.Reader", Run: readersRead},
+		{Name: "readersRot13", Description: "io.Readers that wrap io.Readers", Run: readersRot13},
+		{Name: "imagineImages", Description: "the image package", RunEnv: imagineImages},
+		{Name: "genericTypeParams", Description: "type parameters with comparable", Run: genericTypeParams},
+		{Name: "genericTypes", Description: "a generic linked list", Run: genericTypes},
+	} {
+		l.Topic = "methods"
+		lesson.Register(l)
+	}
+}
 
-func main() {
-	// go does not have classes. you can define methods on a type though
-	// seems like kotlin extension functions
-	methodsExt()
-	interfaceEx()
-	emptyInterface()
-	typeAssertion()
-	typeSwitch()
-	stringers()
-	errorsErr()
-	readersRead()
-	imagineImages()
-	genericTypeParams()
-}
-
-func methodsExt() {
-	v := Vertex{3, 4}
-	fmt.Println(v.Abs())
+func methodsExt(w io.Writer) {
+	v := Vertex{X: 3, Y: 4}
+	fmt.Fprintln(w, v.Abs())
 	v.Scale(10)
-	fmt.Println(v.Abs())
+	fmt.Fprintln(w, v.Abs())
 }
 
-// method (kinda like an extension function)
-func (v Vertex) Abs() float64 {
-	return math.Sqrt(v.X*v.X + v.Y*v.Y)
-}
+// the rest of the vector maths is in geo/vec2.go too
+func vertexMath(w io.Writer) {
+	v, u := Vertex{X: 3, Y: 4}, Vertex{X: 1, Y: 0}
+	fmt.Fprintln(w, v, u, v.Add(u), v.Sub(u))
+	fmt.Fprintln(w, v.Dot(u), v.Cross(u), v.Distance(u))
+	fmt.Fprintln(w, v.Normalize(), v.Lerp(u, 0.5))
 
-/*
-pointer receiver. ie you can create methods on *T receivers
-Methods with pointer receivers can modify the value to which the receiver points (as Scale does here).
-Since methods often need to modify their receiver, pointer receivers are more common than value receivers.
-changing to v Vertex would have a different result b/c value receivers would operate on a copy of V. not V itself
-*/
-func (v *Vertex) Scale(f float64) {
-	v.X = v.X * f
-	v.Y = v.Y * f
+	// a quarter turn. Rotate gives 6.123233995736766e-17 instead of 0, which is
+	// why ApproxEqual exists
+	r := u.Rotate(math.Pi / 2)
+	fmt.Fprintln(w, r == Vertex{X: 0, Y: 1}, r.ApproxEqual(Vertex{X: 0, Y: 1}, 1e-9))
+
+	b, _ := json.Marshal(map[string]Vertex{"v": v})
+	fmt.Fprintln(w, string(b))
 }
 
 /*
@@ -85,7 +106,7 @@ An interface type is defined as a set of method signatures.
 A value of interface type can hold any value that implements those methods
 */
 type I interface {
-	M()
+	M(w io.Writer)
 }
 
 type T struct {
@@ -94,77 +115,79 @@ type T struct {
 
 // This method means type T implements the interface I,
 // but we don't need to explicitly declare that it does so.
-func (t T) M() {
-	fmt.Println(t.S)
+func (t T) M(w io.Writer) {
+	fmt.Fprintln(w, t.S)
 }
 
-func interfaceEx() {
+func interfaceEx(w io.Writer) {
 	var i I = T{"hello"}
-	i.M()
+	i.M(w)
 }
 
 // interface{} is an empty interface. it can hold values of any type
 // since every type implements at least 0 methods
-func emptyInterface() {
+func emptyInterface(w io.Writer) {
 	var i interface{}
-	describe(i)
+	describe(w, i)
 	// note interfaces will populate default values with nil if need be
 	// will just print nil. no null pointer exception or the like
 
 	i = 42
-	describe(i)
+	describe(w, i)
 
 	i = "hello"
-	describe(i)
+	describe(w, i)
 }
 
-func describe(i interface{}) {
-	fmt.Printf("(%v, %T)\n", i, i)
+func describe(w io.Writer, i interface{}) {
+	fmt.Fprintf(w, "(%v, %T)\n", i, i)
 }
 
 // A type assertion provides access to an interface value's underlying concrete value.
-func typeAssertion() {
+func typeAssertion(w io.Writer) {
 	var i interface{} = "hello"
 
 	// type assertion of the form    t := i.(T)
 	s := i.(string)
-	fmt.Println(s)
+	fmt.Fprintln(w, s)
 
 	s, ok := i.(string)
-	fmt.Println(s, ok)
+	fmt.Fprintln(w, s, ok)
 
 	f, ok := i.(float64)
-	fmt.Println(f, ok)
+	fmt.Fprintln(w, f, ok)
 
-	//f = i.(float64) // would panic due to interface conversion
-	fmt.Println(f)
+	// f = i.(float64) would panic due to interface conversion. errs.Assert is the
+	// same assertion but it hands back an error saying what went wrong instead
+	f, err := errs.Assert[float64](i)
+	fmt.Fprintln(w, f, err, errors.Is(err, errs.ErrWrongType))
 }
 
-func typeSwitch() {
-	do(21)
-	do("hello")
-	do(true)
+func typeSwitch(w io.Writer) {
+	do(w, 21)
+	do(w, "hello")
+	do(w, true)
 }
 
 // you can have switch statements that use type to determine branch execution
-func do(i interface{}) {
+func do(w io.Writer, i interface{}) {
 	switch v := i.(type) {
 	case int:
-		fmt.Printf("Twice %v is %v\n", v, v*2)
+		fmt.Fprintf(w, "Twice %v is %v\n", v, v*2)
 	case string:
-		fmt.Printf("%q is %v bytes long\n", v, len(v))
+		fmt.Fprintf(w, "%q is %v bytes long\n", v, len(v))
 	default:
-		fmt.Printf("I don't know about type %T!\n", v)
+		fmt.Fprintf(w, "I don't know about type %T!\n", v)
 	}
 }
 
 // One of the most ubiquitous interfaces is Stringer defined by the fmt package
 // A Stringer is a type that can describe itself as a string. The fmt package (and many others)
 // look for this interface to print values
-func stringers() {
+func stringers(w io.Writer) {
 	a := Person{"Arthur Dent", 42}
 	z := Person{"Zaphod Beeblebrox", 9001}
-	fmt.Println(a, z)
+	fmt.Fprintln(w, a, z)
 }
 
 type Person struct {
@@ -178,19 +201,63 @@ func (p Person) String() string {
 
 // Go programs express error state with error values.
 // The error type is a built-in interface similar to fmt.Stringer
-func errorsErr() {
-	i, err := strconv.Atoi("42gfd") // would work for "42"
+func errorsErr(w io.Writer) {
+	i, err := errs.Atoi("42gfd") // would work for "42"
 	if err != nil {
-		fmt.Printf("couldn't convert number: %v\n", err)
+		fmt.Fprintf(w, "couldn't convert number: %v\n", err)
+
+		// the error is layers wrapped round strconv's own error with %w.
+		// Render shows what each layer added
+		errs.Render(w, err)
+
+		// errors.Is checks every layer for a value, errors.As for a type
+		var num *strconv.NumError
+		fmt.Fprintln(w, errors.Is(err, errs.ErrInvalid), errors.Is(err, strconv.ErrSyntax), errors.As(err, &num), num.Func)
+
+		// carry on past failures and report them all at the end
+		var c errs.Collector
+		for _, s := range []string{"7", "x7", "99999999999999999999"} {
+			_, err := errs.Atoi(s)
+			c.Add(err)
+		}
+		fmt.Fprintln(w, c.Err())
+		fmt.Fprintln(w, c.Len(), errors.Is(c.Err(), errs.ErrOutOfRange))
 		return
 	}
-	fmt.Println("Converted integer:", i)
+	fmt.Fprintln(w, "Converted integer:", i)
 	// Functions often return an error value, and calling code should handle errors by
 	// testing whether the error equals nil
 }
 
+// any type with an Error() string method is an error. numeric.ErrNegativeSqrt
+// is just a float64 with one
+func errorsSqrt(w io.Writer) {
+	for _, x := range []float64{2, -2} {
+		z, err := numeric.Sqrt(x)
+		fmt.Fprintln(w, z, err)
+	}
+
+	// wrapped with %w the original error is still in there. errors.As finds
+	// it and hands back the typed value, bad number and all
+	_, err := numeric.Sqrt(-9)
+	err = fmt.Errorf("working out the side: %w", err)
+	var neg numeric.ErrNegativeSqrt
+	if errors.As(err, &neg) {
+		fmt.Fprintln(w, err, "| the number was", float64(neg))
+	}
+
+	// every guess on the way to sqrt(2). the diff column is how far off
+	// math.Sqrt each guess was
+	_, trace, _ := numeric.Newton{}.Trace(2)
+	fmt.Fprint(w, trace)
+
+	// a loose tolerance stops sooner
+	z, trace, _ := numeric.Newton{Tolerance: 1e-3}.Trace(2)
+	fmt.Fprintln(w, z, len(trace.Steps), "steps")
+}
+
 // The io package specifies the io.Reader interface, which represents the read end of a stream of data
-func readersRead() {
+func readersRead(w io.Writer) {
 	/*
 		Read populates the given byte slice with data and returns the number of bytes populated and an error value.
 		It returns an io.EOF error when the stream ends.
@@ -201,50 +268,128 @@ func readersRead() {
 	b := make([]byte, 8)
 	for {
 		n, err := r.Read(b)
-		fmt.Printf("n = %v err = %v b = %v\n", n, err, b)
-		fmt.Printf("b[:n] = %q\n", b[:n])
+		fmt.Fprintf(w, "n = %v err = %v b = %v\n", n, err, b)
+		fmt.Fprintf(w, "b[:n] = %q\n", b[:n])
+		if err == io.EOF {
+			break
+		}
+	}
+}
+
+// a common pattern is an io.Reader that wraps another io.Reader, modifying the
+// stream in some way. anything with a Read method will do, so the wrappers in
+// readers stack up
+func readersRot13(w io.Writer) {
+	s := strings.NewReader("Lbh penpxrq gur pbqr!")
+	r := readers.Rot13Reader{R: s}
+	io.Copy(w, r)
+	fmt.Fprintln(w)
+
+	// the same 8 bytes at a time loop as readersRead, through two wrappers
+	r2 := readers.NewReader(readers.NewReader(strings.NewReader("Lbh penpxrq gur pbqr!"), readers.Rot13()), readers.Upper())
+	b := make([]byte, 8)
+	for {
+		n, err := r2.Read(b)
+		fmt.Fprintf(w, "%q ", b[:n])
 		if err == io.EOF {
 			break
 		}
 	}
+	fmt.Fprintln(w)
+
+	// writers wrap writers the same way. the vigenère cipher going out,
+	// undone coming back in
+	var out strings.Builder
+	enc, _ := readers.Vigenere("lemon", false)
+	fmt.Fprint(readers.NewWriter(&out, enc), "Attack at dawn")
+	dec, _ := readers.Vigenere("lemon", true)
+	back, _ := io.ReadAll(readers.NewReader(strings.NewReader(out.String()), dec))
+	fmt.Fprintln(w, out.String(), "->", string(back))
+
+	// MyReader never runs out of A's. LimitReader stops it
+	a, _ := io.ReadAll(&readers.LineReader{R: io.LimitReader(readers.MyReader{}, 10)})
+	fmt.Fprintf(w, "%s\n", a)
 }
 
 // Package image defines the Image interface
-func imagineImages() {
+func imagineImages(env *lesson.Env) {
+	w := env.Out
 	m := image.NewRGBA(image.Rect(0, 0, 100, 100))
-	fmt.Println(m.Bounds())
-	fmt.Println(m.At(0, 0).RGBA())
+	fmt.Fprintln(w, m.Bounds())
+	r, g, b, a := m.At(0, 0).RGBA()
+	fmt.Fprintln(w, r, g, b, a)
+
+	// image.Image is just ColorModel, Bounds and At. pic.Gradient stores no
+	// pixels, At works each one out from where it is
+	grad := pic.Gradient{Rect: image.Rect(0, 0, 100, 100), From: color.Black, To: color.White}
+	fmt.Fprintln(w, grad.Bounds(), grad.At(0, 0), grad.At(99, 0))
+
+	// any func(x, y int) color.Color can be an image too
+	xor := pic.Func{Rect: image.Rect(0, 0, 64, 32), F: func(x, y int) color.Color {
+		return color.Gray{uint8((x ^ y) * 4)}
+	}}
+	r, g, b, a = xor.At(3, 5).RGBA()
+	fmt.Fprintln(w, r, g, b, a)
+
+	// run with --verbose in a terminal to see them
+	if env.Verbose {
+		pic.WriteANSI(w, grad, 32)
+		pic.WriteANSI(w, xor, 32)
+	}
 }
 
-func genericTypeParams() {
+func genericTypeParams(w io.Writer) {
 	// Index works on a slice of ints
 	si := []int{10, 20, 15, -10}
-	fmt.Println(Index(si, 15))
+	fmt.Fprintln(w, Index(si, 15))
 
 	// Index also works on a slice of strings
 	ss := []string{"foo", "bar", "baz"}
-	fmt.Println(Index(ss, "hello"))
+	fmt.Fprintln(w, Index(ss, "hello"))
 }
 
 // Go functions can be written to work on multiple types using type parameters.
 // The type parameters of a function appear between brackets, before the function's arguments
-// Index returns the index of x in s, or -1 if not found.
+// Index returns the index of x in s, or -1 if not found. it lives in
+// sliceutil now with the rest of the toolbox, and it is just
+//
+//	for i, v := range s {
+//		// v and x are type T, which has the comparable
+//		// constraint, so we can use == here.
+//		if v == x {
+//			return i
+//		}
+//	}
+//	return -1
 func Index[T comparable](s []T, x T) int {
-	for i, v := range s {
-		// v and x are type T, which has the comparable
-		// constraint, so we can use == here.
-		if v == x {
-			return i
-		}
-	}
-	return -1
+	return sliceutil.Index(s, x)
 }
 
-//In addition to generic functions, Go also supports generic types. A type can be parameterized with a
-// type parameter, which could be useful for implementing generic data structures
-// List represents a singly-linked list that holds
-// values of any type.
-type List[T any] struct {
-	next *List[T]
-	val  T
+// In addition to generic functions, Go also supports generic types. A type can be parameterized with a
+// type parameter, which could be useful for implementing generic data structures.
+// the list package has one. it started out as just
+//
+//	type List[T any] struct {
+//		next *List[T]
+//		val  T
+//	}
+func genericTypes(w io.Writer) {
+	l := list.New(1, 2, 3)
+	l.PushFront(0)
+	l.Push(4)
+	fmt.Fprintln(w, l, l.Len())
+
+	// range over func. All returns an iter.Seq[int]
+	for v := range l.All() {
+		fmt.Fprint(w, v*v, " ")
+	}
+	fmt.Fprintln(w)
+
+	l.Reverse()
+	fmt.Fprintln(w, l, list.Find(l, 3))
+
+	// the same code works for strings. T is inferred from the arguments
+	words := list.FromSlice([]string{"foo", "bar", "baz"})
+	last, _ := words.Pop()
+	fmt.Fprintln(w, words, last, words.Slice())
 }
@@ -0,0 +1,146 @@
+// Package numeric is where the tour's loops and functions exercise ends up.
+// Sqrt finds square roots with Newton's method, guessing z and improving the
+// guess over and over until it stops changing
+package numeric
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"text/tabwriter"
+)
+
+// ErrNegativeSqrt is returned for a negative x. it's a float64 with an
+// Error method, so it satisfies the error interface and still knows the bad
+// number. errors.As gets it back out of a wrapped error
+type ErrNegativeSqrt float64
+
+func (e ErrNegativeSqrt) Error() string {
+	// Sprint(float64(e)), not Sprint(e). e is an error so fmt would call
+	// e.Error() again, and again, forever
+	return fmt.Sprint("cannot Sqrt negative number: ", float64(e))
+}
+
+// ErrNoConvergence means Sqrt ran out of iterations before the guess settled
+var ErrNoConvergence = errors.New("did not converge")
+
+// Newton holds the settings for Sqrt. the zero value is ready to use
+type Newton struct {
+	// Tolerance is how small a change between guesses, relative to the
+	// guess, counts as settled. 0 means keep going until it stops changing
+	// at all, which is within a bit of math.Sqrt
+	Tolerance float64
+
+	// MaxIter caps the iterations. 0 means 100, far more than needed with
+	// the starting guess used here
+	MaxIter int
+}
+
+// Step is one guess
+type Step struct {
+	Iter  int
+	Guess float64
+	Diff  float64 // Guess - math.Sqrt(x)
+}
+
+// Trace is every guess Sqrt made on the way to an answer
+type Trace struct {
+	X     float64
+	Steps []Step
+}
+
+// String shows the guesses as a table next to math.Sqrt
+func (t Trace) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sqrt(%g), math.Sqrt says %v\n", t.X, math.Sqrt(t.X))
+	tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "iter\tguess\tdiff\t")
+	for _, s := range t.Steps {
+		fmt.Fprintf(tw, "%d\t%v\t%.3g\t\n", s.Iter, s.Guess, s.Diff)
+	}
+	tw.Flush()
+	return b.String()
+}
+
+// Sqrt is Newton{}.Sqrt
+func Sqrt(x float64) (float64, error) {
+	return Newton{}.Sqrt(x)
+}
+
+// Sqrt returns the square root of x. NaN, 0 and +Inf come straight back, the
+// same as math.Sqrt, and a negative x is an ErrNegativeSqrt
+func (n Newton) Sqrt(x float64) (float64, error) {
+	z, _, err := n.sqrt(x, false)
+	return z, err
+}
+
+// Trace is Sqrt that also keeps every guess
+func (n Newton) Trace(x float64) (float64, Trace, error) {
+	z, t, err := n.sqrt(x, true)
+	return z, t, err
+}
+
+// polish picks whichever of z and its two neighbouring floats squares closest
+// to x. Newton's method lands within one of the right answer but rounding
+// can leave it a float off. FMA works out c*c - x without rounding c*c first,
+// so the comparison is exact as long as c*c - x isn't a denormal itself.
+// that isn't quite the same as picking the float nearest the real root, but
+// it matched math.Sqrt on all of 10 million random bit patterns
+func polish(z, x float64) float64 {
+	if x < 0x1p-900 {
+		// c*c - x is around x/2^52, so for tiny x it ends up down among the
+		// denormals and gets rounded to their coarser spacing. about 2 in
+		// 10000 random bit patterns used to come out a float off because of
+		// it. compare 2^200 higher up instead, the root moves by exactly 2^100
+		return polish(z*0x1p100, x*0x1p200) / 0x1p100
+	}
+	best := z
+	for _, c := range []float64{math.Nextafter(z, 0), math.Nextafter(z, math.Inf(1))} {
+		if math.Abs(math.FMA(c, c, -x)) < math.Abs(math.FMA(best, best, -x)) {
+			best = c
+		}
+	}
+	return best
+}
+
+func (n Newton) sqrt(x float64, trace bool) (float64, Trace, error) {
+	t := Trace{X: x}
+	switch {
+	case x < 0:
+		return math.NaN(), t, ErrNegativeSqrt(x)
+	case x == 0 || math.IsNaN(x) || math.IsInf(x, 1):
+		// -0 too, which is why this returns x and not 0
+		return x, t, nil
+	}
+	maxIter := n.MaxIter
+	if maxIter <= 0 {
+		maxIter = 100
+	}
+
+	// start from a power of two near the answer. x = frac * 2^exp, so the
+	// root is about 2^(exp/2). starting at 1 or x/2 takes hundreds of steps
+	// for 1e300, and Frexp copes with denormals like 5e-324 as well
+	_, exp := math.Frexp(x)
+	z := math.Ldexp(1, exp/2)
+	want := math.Sqrt(x)
+	prev := math.NaN()
+	for i := 1; i <= maxIter; i++ {
+		// the usual form is z -= (z*z - x) / (2*z). this is the same thing
+		// rearranged, and doesn't overflow or underflow squaring z
+		next := (z + x/z) / 2
+		if trace {
+			t.Steps = append(t.Steps, Step{Iter: i, Guess: next, Diff: next - want})
+		}
+		// at the very end it can flip between two neighbouring floats
+		// forever, so seeing the guess from two steps ago also means done
+		if next == z || next == prev {
+			return polish(next, x), t, nil
+		}
+		if math.Abs(next-z) <= n.Tolerance*next {
+			return next, t, nil
+		}
+		prev, z = z, next
+	}
+	return z, t, fmt.Errorf("numeric: Sqrt(%v) %w after %d iterations", x, ErrNoConvergence, maxIter)
+}
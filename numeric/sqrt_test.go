@@ -0,0 +1,112 @@
+package numeric
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSqrt(t *testing.T) {
+	for _, x := range []float64{
+		1, 2, 4, 0.25, 1e-10, 1e300, math.MaxFloat64,
+		5e-324,                       // the smallest denormal
+		0x1p-1022,                    // the smallest normal
+		math.Nextafter(0x1p-1022, 0), // the biggest denormal
+		math.SmallestNonzeroFloat64 * 3,
+	} {
+		got, err := Sqrt(x)
+		if err != nil {
+			t.Errorf("Sqrt(%g): %v", x, err)
+			continue
+		}
+		if want := math.Sqrt(x); got != want {
+			t.Errorf("Sqrt(%g) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestSqrtSpecial(t *testing.T) {
+	if got, err := Sqrt(math.Inf(1)); !math.IsInf(got, 1) || err != nil {
+		t.Errorf("Sqrt(+Inf) = %v, %v", got, err)
+	}
+	if got, err := Sqrt(math.NaN()); !math.IsNaN(got) || err != nil {
+		t.Errorf("Sqrt(NaN) = %v, %v", got, err)
+	}
+	if got, err := Sqrt(0); got != 0 || math.Signbit(got) || err != nil {
+		t.Errorf("Sqrt(0) = %v, %v", got, err)
+	}
+	// -0 isn't negative, and keeps its sign like math.Sqrt does
+	if got, err := Sqrt(math.Copysign(0, -1)); got != 0 || !math.Signbit(got) || err != nil {
+		t.Errorf("Sqrt(-0) = %v, %v", got, err)
+	}
+}
+
+func TestSqrtNegative(t *testing.T) {
+	for _, x := range []float64{-1, -5e-324, math.Inf(-1)} {
+		got, err := Sqrt(x)
+		if !math.IsNaN(got) {
+			t.Errorf("Sqrt(%g) = %v, want NaN", x, got)
+		}
+		// wrap it the way a caller would, errors.As still finds it
+		wrapped := fmt.Errorf("working out a distance: %w", err)
+		var neg ErrNegativeSqrt
+		if !errors.As(wrapped, &neg) {
+			t.Errorf("Sqrt(%g) error %v isn't an ErrNegativeSqrt", x, err)
+		} else if float64(neg) != x {
+			t.Errorf("Sqrt(%g) error holds %v", x, float64(neg))
+		}
+	}
+	if got := ErrNegativeSqrt(-2).Error(); got != "cannot Sqrt negative number: -2" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestSqrtRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 100000
+	if testing.Short() {
+		n = 1000
+	}
+	for range n {
+		// every bit pattern is equally likely, so this covers denormals and
+		// huge numbers as well as ordinary ones
+		x := math.Float64frombits(r.Uint64() &^ (1 << 63))
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			continue
+		}
+		if got, _ := Sqrt(x); got != math.Sqrt(x) {
+			t.Fatalf("Sqrt(%v) = %v, want %v", x, got, math.Sqrt(x))
+		}
+	}
+}
+
+func TestSqrtNoConvergence(t *testing.T) {
+	_, err := Newton{MaxIter: 1}.Sqrt(1e300)
+	if !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("got %v, want ErrNoConvergence", err)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	z, tr, err := Newton{}.Trace(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z != math.Sqrt2 {
+		t.Errorf("Trace(2) = %v, want %v", z, math.Sqrt2)
+	}
+	if len(tr.Steps) == 0 {
+		t.Fatal("no steps")
+	}
+	for i, s := range tr.Steps {
+		if s.Iter != i+1 || s.Diff != s.Guess-math.Sqrt2 {
+			t.Errorf("step %d is %+v", i, s)
+		}
+	}
+	// the last guess is the answer, give or take the final polish
+	if last := tr.Steps[len(tr.Steps)-1]; math.Abs(last.Diff) > 1e-15 {
+		t.Errorf("last guess %v is %g out", last.Guess, last.Diff)
+	}
+}
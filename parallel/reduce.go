@@ -0,0 +1,114 @@
+// Package parallel grows the two goroutine sum from the concurrency lesson
+// into something reusable. the slice is cut into one piece per worker, each
+// worker reduces its piece on its own goroutine and sends the answer back
+// over a channel, then the pieces are combined in order
+package parallel
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// WorkerStats says what one worker did
+type WorkerStats struct {
+	Worker     int
+	Start, End int // the worker reduced s[Start:End]
+	Elapsed    time.Duration
+}
+
+// Stats is returned alongside the result of Reduce
+type Stats struct {
+	Workers []WorkerStats
+	Elapsed time.Duration // wall time for the whole reduce
+}
+
+// how many elements a worker handles between checks for cancellation
+const checkEvery = 1 << 12
+
+type partial[T any] struct {
+	worker int
+	val    T
+	stats  WorkerStats
+	err    error
+}
+
+// Reduce combines the elements of s using workers goroutines. workers <= 0
+// means runtime.NumCPU(). combine has to be associative, (a+b)+c == a+(b+c),
+// because every worker starts on its own piece. it doesn't have to be
+// commutative though, pieces are combined in slice order.
+//
+// an empty s reduces to the zero value of T. if ctx is cancelled Reduce stops
+// early and returns ctx.Err()
+func Reduce[T any](ctx context.Context, s []T, workers int, combine func(T, T) T) (T, Stats, error) {
+	var zero T
+	start := time.Now()
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	// no point having workers with nothing to do
+	workers = min(workers, len(s))
+	if workers == 0 {
+		return zero, Stats{}, ctx.Err()
+	}
+
+	// buffered so a worker never blocks sending after we gave up on it
+	c := make(chan partial[T], workers)
+	size := len(s) / workers
+	extra := len(s) % workers
+	lo := 0
+	for w := 0; w < workers; w++ {
+		hi := lo + size
+		if w < extra { // spread the remainder over the first few workers
+			hi++
+		}
+		go reduce(ctx, w, s, lo, hi, combine, c)
+		lo = hi
+	}
+
+	parts := make([]partial[T], workers)
+	for range workers {
+		select {
+		case p := <-c:
+			if p.err != nil {
+				return zero, Stats{}, p.err
+			}
+			parts[p.worker] = p
+		case <-ctx.Done():
+			return zero, Stats{}, ctx.Err()
+		}
+	}
+
+	stats := Stats{Workers: make([]WorkerStats, workers)}
+	acc := parts[0].val
+	for i, p := range parts {
+		if i > 0 {
+			acc = combine(acc, p.val)
+		}
+		stats.Workers[i] = p.stats
+	}
+	stats.Elapsed = time.Since(start)
+	return acc, stats, nil
+}
+
+func reduce[T any](ctx context.Context, worker int, s []T, lo, hi int, combine func(T, T) T, c chan<- partial[T]) {
+	start := time.Now()
+	acc := s[lo]
+	for i := lo + 1; i < hi; i++ {
+		if (i-lo)%checkEvery == 0 && ctx.Err() != nil {
+			c <- partial[T]{worker: worker, err: ctx.Err()}
+			return
+		}
+		acc = combine(acc, s[i])
+	}
+	c <- partial[T]{
+		worker: worker,
+		val:    acc,
+		stats:  WorkerStats{Worker: worker, Start: lo, End: hi, Elapsed: time.Since(start)},
+	}
+}
+
+// Sum adds up s with Reduce. it's channelsChan's sum for any number of workers
+func Sum[T int | int64 | float64](ctx context.Context, s []T, workers int) (T, Stats, error) {
+	return Reduce(ctx, s, workers, func(a, b T) T { return a + b })
+}
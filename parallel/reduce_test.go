@@ -0,0 +1,94 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 1000, 10001} {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i + 1
+		}
+		for _, workers := range []int{0, 1, 3, 16} {
+			got, stats, err := Sum(context.Background(), s, workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := n * (n + 1) / 2; got != want {
+				t.Errorf("n=%d workers=%d: got %d, want %d", n, workers, got, want)
+			}
+			// the pieces cover s exactly, in order
+			end := 0
+			for _, w := range stats.Workers {
+				if w.Start != end {
+					t.Errorf("n=%d workers=%d: piece starts at %d, want %d", n, workers, w.Start, end)
+				}
+				end = w.End
+			}
+			if end != n && n > 0 {
+				t.Errorf("n=%d workers=%d: pieces end at %d", n, workers, end)
+			}
+		}
+	}
+}
+
+// combine only has to be associative, the pieces go back together in order
+func TestReduceKeepsOrder(t *testing.T) {
+	s := strings.Split("the quick brown fox jumps over the lazy dog", "")
+	got, _, err := Reduce(context.Background(), s, 4, func(a, b string) string { return a + b })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Join(s, ""); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestReduceCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := make([]int, 1<<16)
+	if _, _, err := Sum(ctx, s, 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+var sink int
+
+// BenchmarkReduce shows where splitting the work starts to pay. for small
+// slices starting goroutines costs more than the adding and the plain loop
+// wins. on one cpu extra workers never help, try it on a bigger machine
+func BenchmarkReduce(b *testing.B) {
+	workers := []int{1, 2, 4, runtime.NumCPU()}
+	slices.Sort(workers)
+	workers = slices.Compact(workers)
+	for _, n := range []int{100, 10_000, 1_000_000, 10_000_000} {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i
+		}
+		b.Run(fmt.Sprintf("n=%d/loop", n), func(b *testing.B) {
+			for b.Loop() {
+				sum := 0
+				for _, v := range s {
+					sum += v
+				}
+				sink = sum
+			}
+		})
+		for _, w := range workers {
+			b.Run(fmt.Sprintf("n=%d/workers=%d", n, w), func(b *testing.B) {
+				for b.Loop() {
+					sink, _, _ = Sum(context.Background(), s, w)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/DaveM7788/tourOfGo/pic"
+)
+
+// picImages are the images tour pic can draw, at a given size
+var picImages = map[string]func(w, h int) image.Image{
+	"gradient": func(w, h int) image.Image {
+		return pic.Gradient{Rect: image.Rect(0, 0, w, h), From: color.RGBA{255, 64, 0, 255}, To: color.RGBA{0, 64, 255, 255}}
+	},
+	"xor": func(w, h int) image.Image {
+		return pic.Func{Rect: image.Rect(0, 0, w, h), F: func(x, y int) color.Color {
+			v := uint8(x ^ y)
+			return color.RGBA{v, 255 - v, v / 2, 255}
+		}}
+	},
+	"rings": func(w, h int) image.Image {
+		return pic.Palette{
+			Rect:    image.Rect(0, 0, w, h),
+			Palette: color.Palette{color.White, color.RGBA{255, 0, 0, 255}, color.RGBA{255, 200, 0, 255}, color.RGBA{0, 160, 0, 255}, color.RGBA{0, 0, 255, 255}, color.Black},
+			Index: func(x, y int) int {
+				dx, dy := x-w/2, y-h/2
+				// small images would divide by 0
+				return (dx*dx + dy*dy) / max(1, w*h/32)
+			},
+		}
+	},
+	// the tour's slices exercise, Pic(dx, dy) [][]uint8
+	"pic": func(w, h int) image.Image {
+		p := make([][]uint8, h)
+		for y := range p {
+			p[y] = make([]uint8, w)
+			for x := range p[y] {
+				p[y][x] = uint8((x + y) / 2)
+			}
+		}
+		return pic.FromPic(p)
+	},
+}
+
+var picFormats = map[string]func(io.Writer, image.Image) error{
+	"png": pic.WritePNG,
+	"ppm": pic.WritePPM,
+	"pgm": pic.WritePGM,
+}
+
+// picCmd draws one of our image types in the terminal or saves it
+func picCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("pic", flag.ContinueOnError)
+	size := fs.String("size", "256x256", "image size, `WxH`")
+	out := fs.String("out", "", "save to this file instead of drawing in the terminal. .png, .ppm or .pgm")
+	cols := fs.Int("cols", 64, "how many characters wide to draw in the terminal")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("which image? one of %s", strings.Join(slices.Sorted(maps.Keys(picImages)), ", "))
+	}
+	newImage, ok := picImages[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("no image called %q. try one of %s", fs.Arg(0), strings.Join(slices.Sorted(maps.Keys(picImages)), ", "))
+	}
+	var width, height int
+	if _, err := fmt.Sscanf(*size, "%dx%d", &width, &height); err != nil || width < 1 || height < 1 {
+		return fmt.Errorf("--size wants something like 256x256, not %q", *size)
+	}
+	return saveImage(w, newImage(width, height), *out, *cols)
+}
+
+// saveImage writes m to the file out in the format its extension says, or
+// draws it on w when out is empty
+func saveImage(w io.Writer, m image.Image, out string, cols int) error {
+	if out == "" {
+		return pic.WriteANSI(w, m, cols)
+	}
+	write, ok := picFormats[strings.TrimPrefix(filepath.Ext(out), ".")]
+	if !ok {
+		return fmt.Errorf("can't write %s. use .png, .ppm or .pgm", out)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := write(f, m); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
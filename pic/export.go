@@ -0,0 +1,81 @@
+package pic
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// WritePNG is png.Encode. it's here so all the formats are in one place
+func WritePNG(w io.Writer, m image.Image) error {
+	return png.Encode(w, m)
+}
+
+// WritePPM writes the binary PPM format, P6. it's about the simplest image
+// file there is, a short text header then three bytes per pixel, and most
+// image viewers open it
+func WritePPM(w io.Writer, m image.Image) error {
+	bw := bufio.NewWriter(w)
+	b := m.Bounds()
+	fmt.Fprintf(bw, "P6\n%d %d\n255\n", b.Dx(), b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(m.At(x, y)).(color.NRGBA)
+			bw.Write([]byte{c.R, c.G, c.B})
+		}
+	}
+	return bw.Flush()
+}
+
+// WritePGM writes P5, PPM's greyscale sibling with one byte per pixel
+func WritePGM(w io.Writer, m image.Image) error {
+	bw := bufio.NewWriter(w)
+	b := m.Bounds()
+	fmt.Fprintf(bw, "P5\n%d %d\n255\n", b.Dx(), b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			bw.WriteByte(color.GrayModel.Convert(m.At(x, y)).(color.Gray).Y)
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteANSI draws m in the terminal, cols characters wide. each character is
+// an upper half block ▀ with its foreground set to one pixel and background
+// to the one below, so a row of text shows two rows of pixels and the
+// pixels come out roughly square. needs a terminal with 24 bit colour, which
+// is most of them now. cols <= 0 means the image's own width
+func WriteANSI(w io.Writer, m image.Image, cols int) error {
+	b := m.Bounds()
+	if b.Empty() {
+		return nil
+	}
+	if cols <= 0 || cols > b.Dx() {
+		cols = b.Dx()
+	}
+	// nearest neighbour. pick whichever pixel each cell lands on
+	scale := float64(b.Dx()) / float64(cols)
+	rows := int(float64(b.Dy())/scale+0.5) / 2 * 2
+	rows = max(rows, 2)
+	at := func(col, row int) color.NRGBA {
+		x := b.Min.X + int(float64(col)*scale)
+		y := b.Min.Y + min(int(float64(row)*scale), b.Dy()-1)
+		return color.NRGBAModel.Convert(m.At(x, y)).(color.NRGBA)
+	}
+
+	var sb strings.Builder
+	for row := 0; row < rows; row += 2 {
+		for col := range cols {
+			top, bottom := at(col, row), at(col, row+1)
+			fmt.Fprintf(&sb, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+		}
+		// reset before the newline or the background bleeds to the edge
+		sb.WriteString("\x1b[0m\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
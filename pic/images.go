@@ -0,0 +1,115 @@
+// Package pic has image.Image types of our own and ways to get them out of
+// the program to look at. image.Image is only three methods, ColorModel,
+// Bounds and At, so an image doesn't need any pixels stored at all. it can
+// work each one out when asked
+package pic
+
+import (
+	"image"
+	"image/color"
+)
+
+// Gradient fades From to To across Rect, left to right or top to bottom
+type Gradient struct {
+	Rect     image.Rectangle
+	From, To color.Color
+	Vertical bool
+}
+
+func (g Gradient) ColorModel() color.Model { return color.RGBA64Model }
+func (g Gradient) Bounds() image.Rectangle { return g.Rect }
+
+func (g Gradient) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(g.Rect)) {
+		return color.RGBA64{}
+	}
+	pos, size := x-g.Rect.Min.X, g.Rect.Dx()
+	if g.Vertical {
+		pos, size = y-g.Rect.Min.Y, g.Rect.Dy()
+	}
+	t := 0.0
+	if size > 1 {
+		t = float64(pos) / float64(size-1)
+	}
+	return Lerp(g.From, g.To, t)
+}
+
+// Lerp mixes a and b, t=0 is all a and t=1 is all b
+func Lerp(a, b color.Color, t float64) color.Color {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	mix := func(p, q uint32) uint16 {
+		return uint16(float64(p) + (float64(q)-float64(p))*t + 0.5)
+	}
+	return color.RGBA64{mix(r1, r2), mix(g1, g2), mix(b1, b2), mix(a1, a2)}
+}
+
+// Func is an image worked out by a function, one pixel at a time
+type Func struct {
+	Rect image.Rectangle
+	F    func(x, y int) color.Color
+}
+
+func (f Func) ColorModel() color.Model { return color.RGBA64Model }
+func (f Func) Bounds() image.Rectangle { return f.Rect }
+
+func (f Func) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(f.Rect)) {
+		return color.RGBA64{}
+	}
+	return f.F(x, y)
+}
+
+// Palette is an image whose pixels are indexes into a list of colours, like
+// a gif. Index says which colour goes where, out of range wraps round. it
+// satisfies image.PalettedImage, so image/png writes it with a palette. with
+// no colours at all every pixel is transparent black, and with no Index every
+// pixel is the first colour
+type Palette struct {
+	Rect    image.Rectangle
+	Palette color.Palette
+	Index   func(x, y int) int
+}
+
+func (p Palette) ColorModel() color.Model { return p.Palette }
+func (p Palette) Bounds() image.Rectangle { return p.Rect }
+
+func (p Palette) ColorIndexAt(x, y int) uint8 {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return 0
+	}
+	n := len(p.Palette)
+	if n == 0 || p.Index == nil {
+		return 0
+	}
+	return uint8(((p.Index(x, y) % n) + n) % n)
+}
+
+func (p Palette) At(x, y int) color.Color {
+	if len(p.Palette) == 0 {
+		return color.RGBA64{}
+	}
+	return p.Palette[p.ColorIndexAt(x, y)]
+}
+
+// FromPic turns the [][]uint8 the tour's slices exercise builds into an
+// image, blue shading the way the tour's pic.Show draws it
+func FromPic(p [][]uint8) image.Image {
+	dy, dx := len(p), 0
+	if dy > 0 {
+		dx = len(p[0])
+	}
+	return Func{Rect: image.Rect(0, 0, dx, dy), F: func(x, y int) color.Color {
+		if x >= len(p[y]) {
+			return color.RGBA{A: 255}
+		}
+		v := p[y][x]
+		return color.RGBA{v, v, 255, 255}
+	}}
+}
+
+var (
+	_ image.Image         = Gradient{}
+	_ image.Image         = Func{}
+	_ image.PalettedImage = Palette{}
+)
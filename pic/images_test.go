@@ -0,0 +1,80 @@
+package pic
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestPaletteWraps(t *testing.T) {
+	p := Palette{
+		Rect:    image.Rect(0, 0, 4, 1),
+		Palette: color.Palette{color.Black, color.White, color.Gray{128}},
+		Index:   func(x, y int) int { return x - 2 },
+	}
+	// -2 -1 0 1 wrap round to 1 2 0 1
+	for x, want := range []uint8{1, 2, 0, 1} {
+		if got := p.ColorIndexAt(x, 0); got != want {
+			t.Errorf("ColorIndexAt(%d, 0) = %d, want %d", x, got, want)
+		}
+	}
+	if got := p.ColorIndexAt(9, 9); got != 0 {
+		t.Errorf("outside Rect got %d, want 0", got)
+	}
+}
+
+func TestPaletteEmpty(t *testing.T) {
+	p := Palette{
+		Rect:  image.Rect(0, 0, 2, 2),
+		Index: func(x, y int) int { return x + y },
+	}
+	if got := p.ColorIndexAt(1, 1); got != 0 {
+		t.Errorf("ColorIndexAt = %d, want 0", got)
+	}
+	if r, g, b, a := p.At(1, 1).RGBA(); r|g|b|a != 0 {
+		t.Errorf("At = %v, want transparent", p.At(1, 1))
+	}
+}
+
+func TestPaletteNoIndex(t *testing.T) {
+	p := Palette{
+		Rect:    image.Rect(0, 0, 2, 2),
+		Palette: color.Palette{color.White, color.Black},
+	}
+	if got := p.ColorIndexAt(1, 1); got != 0 {
+		t.Errorf("ColorIndexAt = %d, want 0", got)
+	}
+	if got := p.At(1, 1); got != color.White {
+		t.Errorf("At = %v, want the first colour", got)
+	}
+	var b bytes.Buffer
+	if err := WritePNG(&b, p); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	m := FromPic([][]uint8{{0, 64}, {128, 255}, {1, 2}})
+	var b bytes.Buffer
+	if err := WritePNG(&b, m); err != nil {
+		t.Fatal(err)
+	}
+	got, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Bounds() != m.Bounds() {
+		t.Fatalf("bounds %v, want %v", got.Bounds(), m.Bounds())
+	}
+	for y := range 3 {
+		for x := range 2 {
+			r1, g1, b1, a1 := got.At(x, y).RGBA()
+			r2, g2, b2, a2 := m.At(x, y).RGBA()
+			if r1>>8 != r2>>8 || g1>>8 != g2>>8 || b1>>8 != b2>>8 || a1>>8 != a2>>8 {
+				t.Errorf("(%d, %d) is %v, want %v", x, y, got.At(x, y), m.At(x, y))
+			}
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"io"
+	"testing"
+)
+
+// every image has to cope with being tiny. rings used to divide by 0
+func TestPicImagesSmall(t *testing.T) {
+	for name := range picImages {
+		for _, size := range []string{"1x1", "4x4", "3x7"} {
+			if err := picCmd(io.Discard, []string{"--size", size, name}); err != nil {
+				t.Errorf("%s at %s: %v", name, size, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/DaveM7788/tourOfGo/clock"
+	"github.com/DaveM7788/tourOfGo/readers"
+)
+
+// a stage can be built as a reader or a writer. either way it's fresh, so
+// stateful ones like the vigenère cipher start from the top of their key
+type stage struct {
+	reader func(io.Reader) io.Reader
+	writer func(io.Writer) io.Writer
+}
+
+func transformStage(t func() readers.Transform) stage {
+	return stage{
+		reader: func(r io.Reader) io.Reader { return readers.NewReader(r, t()) },
+		writer: func(w io.Writer) io.Writer { return readers.NewWriter(w, t()) },
+	}
+}
+
+// pipeCmd copies files, or stdin, to stdout through the readers given as
+// flags. they run in the order they're given, --rot13 --upper undoes rot13
+// and then shouts
+func pipeCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("pipe", flag.ContinueOnError)
+	var stages []stage
+	// flag.Parse doesn't say what order flags came in, but it does call
+	// Set in order. so each flag adds its stage as it's seen
+	add := func(s stage) { stages = append(stages, s) }
+	positive := func(f func(n int) stage) func(string) error {
+		return func(v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return fmt.Errorf("want a whole number above 0, not %q", v)
+			}
+			add(f(n))
+			return nil
+		}
+	}
+	// --upper gives "true". --upper=false is allowed too and adds nothing
+	toggle := func(s stage) func(string) error {
+		return func(v string) error {
+			on, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+			if on {
+				add(s)
+			}
+			return nil
+		}
+	}
+	vigenere := func(decrypt bool) func(string) error {
+		return func(key string) error {
+			if _, err := readers.Vigenere(key, decrypt); err != nil {
+				return err
+			}
+			add(transformStage(func() readers.Transform {
+				t, _ := readers.Vigenere(key, decrypt)
+				return t
+			}))
+			return nil
+		}
+	}
+
+	fs.BoolFunc("rot13", "rot13 the letters", toggle(transformStage(readers.Rot13)))
+	fs.Func("caesar", "shift letters `n` places. negative undoes it", func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		add(transformStage(func() readers.Transform { return readers.Caesar(n) }))
+		return nil
+	})
+	fs.Func("vigenere", "vigenère cipher with `key`", vigenere(false))
+	fs.Func("unvigenere", "undo a vigenère cipher with `key`", vigenere(true))
+	fs.BoolFunc("upper", "upper case", toggle(transformStage(readers.Upper)))
+	fs.BoolFunc("lower", "lower case", toggle(transformStage(readers.Lower)))
+	fs.BoolFunc("number", "number the lines", toggle(stage{
+		reader: func(r io.Reader) io.Reader { return &readers.LineReader{R: r} },
+		writer: func(w io.Writer) io.Writer { return &readers.LineWriter{W: w} },
+	}))
+	fs.Func("chunk", "pass data on `n` bytes at a time", positive(func(n int) stage {
+		return stage{
+			reader: func(r io.Reader) io.Reader { return &readers.ChunkReader{R: r, N: n} },
+			writer: func(w io.Writer) io.Writer { return &readers.ChunkWriter{W: w, N: n} },
+		}
+	}))
+	fs.Func("rate", "slow down to `n` bytes a second", positive(func(n int) stage {
+		return stage{
+			reader: func(r io.Reader) io.Reader { return readers.NewRateReader(r, n, clock.Real) },
+			writer: func(w io.Writer) io.Writer { return readers.NewRateWriter(w, n, clock.Real) },
+		}
+	}))
+	aaaa := fs.Int64("a", 0, "read `n` bytes of MyReader's endless A's instead of a file")
+	writers := fs.Bool("writers", false, "use the io.Writer version of every stage instead")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	switch {
+	case *aaaa > 0:
+		in = io.LimitReader(readers.MyReader{}, *aaaa)
+	case fs.NArg() > 0:
+		var files []io.Reader
+		for _, name := range fs.Args() {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			files = append(files, f)
+		}
+		in = io.MultiReader(files...)
+	}
+
+	if *writers {
+		// the first stage has to see the data first, so it goes on the
+		// outside. build from the last stage in
+		for _, s := range slices.Backward(stages) {
+			w = s.writer(w)
+		}
+	} else {
+		for _, s := range stages {
+			in = s.reader(in)
+		}
+	}
+	_, err := io.Copy(w, in)
+	return err
+}
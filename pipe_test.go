@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// MyReader's A's make the input, so nothing needs stdin
+func TestPipeFlags(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-a", "3"}, "AAA"},
+		{[]string{"-a", "3", "--lower"}, "aaa"},
+		{[]string{"-a", "3", "--lower=true"}, "aaa"},
+		{[]string{"-a", "3", "--lower=false"}, "AAA"},
+		{[]string{"-a", "3", "--lower", "--upper=0"}, "aaa"},
+		{[]string{"-a", "3", "--rot13=false", "--lower"}, "aaa"},
+		{[]string{"-a", "3", "--lower", "--rot13"}, "nnn"},
+		{[]string{"-a", "3", "--writers", "--lower", "--rot13"}, "nnn"},
+	} {
+		var b strings.Builder
+		if err := pipeCmd(&b, tt.args); err != nil {
+			t.Errorf("%q: %v", tt.args, err)
+		} else if b.String() != tt.want {
+			t.Errorf("%q = %q, want %q", tt.args, b.String(), tt.want)
+		}
+	}
+	if err := pipeCmd(new(strings.Builder), []string{"-a", "3", "--upper=loud"}); err == nil {
+		t.Error("--upper=loud was accepted")
+	}
+}
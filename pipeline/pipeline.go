@@ -0,0 +1,253 @@
+// Package pipeline builds channel pipelines out of small generic stages.
+// every stage runs on its own goroutine, reads from a channel and writes to
+// one it returns. stages close their output when their input is closed or
+// when ctx is cancelled, so cancelling ctx shuts the whole pipeline down and
+// no goroutine is left blocked on a send. that replaces the quit channel from
+// the select lesson
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// send blocks until v is sent or ctx is done. it reports whether v was sent
+func send[T any](ctx context.Context, c chan<- T, v T) bool {
+	select {
+	case c <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// recv waits for a value from c. ok is false if c was closed or ctx is done,
+// so a stage never sits forever on an input nobody is going to close
+func recv[T any](ctx context.Context, c <-chan T) (v T, ok bool) {
+	select {
+	case v, ok = <-c:
+		return v, ok
+	case <-ctx.Done():
+		return v, false
+	}
+}
+
+// Generate sends f() over and over until ctx is cancelled
+func Generate[T any](ctx context.Context, f func() T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for send(ctx, out, f()) {
+		}
+	}()
+	return out
+}
+
+// Fibonacci sends 0, 1, 1, 2, 3, 5... until ctx is cancelled. it's
+// fibonacciSel with ctx.Done() standing in for the quit channel
+func Fibonacci(ctx context.Context) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		x, y := 0, 1
+		for {
+			select {
+			case out <- x:
+				x, y = y, x+y
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FromSlice sends the elements of s then closes
+func FromSlice[T any](ctx context.Context, s []T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range s {
+			if !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Take passes on the first n values from in then closes. the caller should
+// still cancel ctx once done so that whatever feeds in stops too
+func Take[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for i := 0; i < n; i++ {
+			v, ok := recv(ctx, in)
+			if !ok || !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Map sends f(v) for every v from in
+func Map[T, U any](ctx context.Context, in <-chan T, f func(T) U) <-chan U {
+	out := make(chan U)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			if !send(ctx, out, f(v)) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Filter passes on the values from in that f is true for
+func Filter[T any](ctx context.Context, in <-chan T, f func(T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			if f(v) && !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FanOut splits in across n channels. each value goes to exactly one of them,
+// whichever reader is ready first, so a slow reader doesn't hold up the rest.
+// put a Map on each output to spread work over n goroutines
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	outs := make([]<-chan T, n)
+	for i := range outs {
+		out := make(chan T)
+		outs[i] = out
+		go func() {
+			defer close(out)
+			for {
+				v, ok := recv(ctx, in)
+				if !ok {
+					return
+				}
+				if !send(ctx, out, v) {
+					return
+				}
+			}
+		}()
+	}
+	return outs
+}
+
+// FanIn merges several channels into one. order between inputs isn't kept.
+// the output closes once every input has closed
+func FanIn[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		go func() {
+			defer wg.Done()
+			for {
+				v, ok := recv(ctx, in)
+				if !ok {
+					return
+				}
+				if !send(ctx, out, v) {
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Tee copies every value from in to both outputs. both have to be read or
+// the pipeline stalls, same as the unix command
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	out1, out2 := make(chan T), make(chan T)
+	go func() {
+		defer close(out1)
+		defer close(out2)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			// send to whichever is ready first, then the other one. setting
+			// a channel to nil takes it out of the select
+			a, b := out1, out2
+			for a != nil || b != nil {
+				select {
+				case a <- v:
+					a = nil
+				case b <- v:
+					b = nil
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out1, out2
+}
+
+// Batch groups values from in into slices of size. the last batch may be
+// short. it panics if size < 1
+func Batch[T any](ctx context.Context, in <-chan T, size int) <-chan []T {
+	if size < 1 {
+		panic("pipeline: Batch size must be at least 1")
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		batch := make([]T, 0, size)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				break
+			}
+			batch = append(batch, v)
+			if len(batch) == size {
+				if !send(ctx, out, batch) {
+					return
+				}
+				batch = make([]T, 0, size)
+			}
+		}
+		if len(batch) > 0 && ctx.Err() == nil {
+			send(ctx, out, batch)
+		}
+	}()
+	return out
+}
+
+// Collect reads everything from in into a slice. it returns early with what
+// it has if ctx is cancelled
+func Collect[T any](ctx context.Context, in <-chan T) []T {
+	var s []T
+	for {
+		v, ok := recv(ctx, in)
+		if !ok {
+			return s
+		}
+		s = append(s, v)
+	}
+}
@@ -0,0 +1,141 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+// noLeaks fails t if the goroutines the test started haven't all exited
+// shortly after it's done. the stages only notice ctx between values, so
+// give them a moment
+func noLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(2 * time.Second)
+		for runtime.NumGoroutine() > before {
+			if time.Now().After(deadline) {
+				buf := make([]byte, 1<<16)
+				buf = buf[:runtime.Stack(buf, true)]
+				t.Errorf("%d goroutines left running, started with %d\n%s", runtime.NumGoroutine(), before, buf)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
+
+func TestStages(t *testing.T) {
+	noLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	got := Collect(ctx, Take(ctx, Fibonacci(ctx), 10))
+	if want := []int{0, 1, 1, 2, 3, 5, 8, 13, 21, 34}; !slices.Equal(got, want) {
+		t.Errorf("Fibonacci = %v, want %v", got, want)
+	}
+
+	odd := Filter(ctx, FromSlice(ctx, []int{1, 2, 3, 4, 5}), func(n int) bool { return n%2 == 1 })
+	got = Collect(ctx, Map(ctx, odd, func(n int) int { return n * n }))
+	if want := []int{1, 9, 25}; !slices.Equal(got, want) {
+		t.Errorf("odd squares = %v, want %v", got, want)
+	}
+
+	batches := Collect(ctx, Batch(ctx, FromSlice(ctx, []int{1, 2, 3, 4, 5}), 2))
+	if fmt.Sprint(batches) != "[[1 2] [3 4] [5]]" {
+		t.Errorf("Batch = %v", batches)
+	}
+
+	n := 0
+	g := Generate(ctx, func() int { n++; return n })
+	if got := Collect(ctx, Take(ctx, g, 3)); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Generate = %v", got)
+	}
+}
+
+func TestFanOutFanIn(t *testing.T) {
+	noLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make([]int, 100)
+	for i := range in {
+		in[i] = i
+	}
+	var outs []<-chan int
+	for _, c := range FanOut(ctx, FromSlice(ctx, in), 4) {
+		outs = append(outs, Map(ctx, c, func(n int) int { return n * 2 }))
+	}
+	got := Collect(ctx, FanIn(ctx, outs...))
+	// order isn't kept, but every value turns up exactly once
+	slices.Sort(got)
+	for i, v := range got {
+		if v != i*2 {
+			t.Fatalf("got %v", got)
+		}
+	}
+	if len(got) != len(in) {
+		t.Errorf("got %d values, want %d", len(got), len(in))
+	}
+}
+
+func TestTee(t *testing.T) {
+	noLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a, b := Tee(ctx, FromSlice(ctx, []string{"x", "y", "z"}))
+	done := make(chan []string)
+	go func() { done <- Collect(ctx, b) }()
+	gotA, gotB := Collect(ctx, a), <-done
+	if !slices.Equal(gotA, []string{"x", "y", "z"}) || !slices.Equal(gotA, gotB) {
+		t.Errorf("Tee gave %v and %v", gotA, gotB)
+	}
+}
+
+// cancelling part way through must stop every stage, even the ones blocked
+// sending to a reader that's gone away
+func TestCancelMidPipeline(t *testing.T) {
+	for _, stop := range []int{0, 1, 5, 50} {
+		t.Run(fmt.Sprint(stop), func(t *testing.T) {
+			noLeaks(t)
+			ctx, cancel := context.WithCancel(context.Background())
+
+			nums := Map(ctx, Fibonacci(ctx), func(n int) int { return n % 1000 })
+			even := Filter(ctx, nums, func(n int) bool { return n%2 == 0 })
+			var outs []<-chan string
+			for _, c := range FanOut(ctx, even, 3) {
+				outs = append(outs, Map(ctx, c, func(n int) string { return fmt.Sprint(n) }))
+			}
+			a, b := Tee(ctx, FanIn(ctx, outs...))
+			batches := Batch(ctx, a, 4)
+			// b has to be read too or Tee stalls. this stops when Tee
+			// closes it, so it's covered by noLeaks as well
+			go func() {
+				for range b {
+				}
+			}()
+
+			for range stop {
+				<-batches
+			}
+			// every stage is now blocked or about to be, and nothing is
+			// going to close their inputs
+			cancel()
+		})
+	}
+}
+
+func TestCancelCollect(t *testing.T) {
+	noLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	// Fibonacci never closes, so only the cancel gets Collect out
+	if got := Collect(ctx, Fibonacci(ctx)); len(got) == 0 {
+		t.Error("Collect got nothing before the cancel")
+	}
+}
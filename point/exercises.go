@@ -0,0 +1,52 @@
+package point
+
+import "github.com/DaveM7788/tourOfGo/exercise"
+
+func init() {
+	exercise.Register(exercise.Exercise{
+		Name:        "fibonacci",
+		Lesson:      "functionClosures",
+		Description: "a closure that returns successive fibonacci numbers",
+		Stub: `package scratch
+
+// fibonacci returns a function that returns successive fibonacci numbers
+// (0, 1, 1, 2, 3, 5, ...) each time it is called. like adder, every closure
+// it returns keeps its own state
+func fibonacci() func() int {
+	// TODO
+	return func() int {
+		return 0
+	}
+}
+`,
+		Tests: `package scratch
+
+import "testing"
+
+var want = []int{0, 1, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89}
+
+func TestFibonacci(t *testing.T) {
+	t.Run("sequence", func(t *testing.T) {
+		f := fibonacci()
+		for i, w := range want {
+			if got := f(); got != w {
+				t.Fatalf("call %d returned %d. want %d", i+1, got, w)
+			}
+		}
+	})
+	t.Run("independent closures", func(t *testing.T) {
+		a, b := fibonacci(), fibonacci()
+		for i := 0; i < 5; i++ {
+			a()
+		}
+		if got := b(); got != 0 {
+			t.Errorf("first call on a fresh closure returned %d after using another one. want 0", got)
+		}
+		if got := a(); got != want[5] {
+			t.Errorf("sixth call returned %d. want %d", got, want[5])
+		}
+	})
+}
+`,
+	})
+}
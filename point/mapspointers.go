@@ -1,61 +1,88 @@
-package main
+package point
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/calc"
+	"github.com/DaveM7788/tourOfGo/clock"
+	"github.com/DaveM7788/tourOfGo/closures"
+	"github.com/DaveM7788/tourOfGo/geo"
+	"github.com/DaveM7788/tourOfGo/lesson"
+	"github.com/DaveM7788/tourOfGo/safemap"
+	"github.com/DaveM7788/tourOfGo/sliceview"
+	"github.com/DaveM7788/tourOfGo/tictactoe"
+	"github.com/DaveM7788/tourOfGo/wordcount"
 )
 
-func main() {
-	pointersPoint()
-	structsStructure()
-	arraysArrange()
-	slicesSlice()
-	slicesLenCap()
-	slicesMake()
-	sliceTacToe()
-	sliceAppend()
-	rangeSimp()
-	mapsMap()
-	mapsMutate()
-	functionValues()
-	functionClosures()
+func init() {
+	for _, l := range []lesson.Lesson{
+		{Name: "pointersPoint", Description: "& and * on ints", Run: pointersPoint},
+		{Name: "structsStructure", Description: "struct literals and pointers to structs", Run: structsStructure},
+		{Name: "arraysArrange", Description: "fixed size arrays", Run: arraysArrange},
+		{Name: "slicesSlice", Description: "slices are views into arrays", RunEnv: slicesSlice},
+		{Name: "slicesLenCap", Description: "slice length and capacity", RunEnv: slicesLenCap},
+		{Name: "slicesMake", Description: "creating slices with make", RunEnv: slicesMake},
+		{Name: "sliceTacToe", Description: "slices of slices", Run: sliceTacToe},
+		{Name: "ticTacToeAI", Description: "the same board played by the tictactoe package", Run: ticTacToeAI},
+		{Name: "sliceAliasing", Description: "drawing which slices share an array", Run: sliceAliasing},
+		{Name: "sliceAppend", Description: "growing a slice with append", RunEnv: sliceAppend},
+		{Name: "rangeSimp", Description: "range over a slice", Run: rangeSimp},
+		{Name: "mapsMap", Description: "map literals", Run: mapsMap},
+		{Name: "mapsDistance", Description: "distances and searches over a map of places", Run: mapsDistance},
+		{Name: "mapsMutate", Description: "insert, update, delete and comma ok", Run: mapsMutate},
+		{Name: "mapsWordCount", Description: "counting words with a map", Run: mapsWordCount},
+		{Name: "mapsConcurrent", Description: "sharing a map between goroutines", Run: mapsConcurrent},
+		{Name: "functionValues", Description: "functions as values", Run: functionValues},
+		{Name: "functionCalc", Description: "a calculator made of function values", Run: functionCalc},
+		{Name: "functionClosures", Description: "closures keep their own state", Run: functionClosures},
+		{Name: "closureToolbox", Description: "memoize, throttle, compose and friends", Run: closureToolbox},
+	} {
+		l.Topic = "point"
+		lesson.Register(l)
+	}
 }
 
-func pointersPoint() {
+func pointersPoint(w io.Writer) {
 	// go has pointers. points to a memory location of a variable. type of *T
 	i, j := 42, 2701
 
-	p := &i         // point to i
-	fmt.Println(*p) // read i through the pointer
-	*p = 21         // set i through the pointer
-	fmt.Println(i)  // see the new value of i
+	p := &i             // point to i
+	fmt.Fprintln(w, *p) // read i through the pointer
+	*p = 21             // set i through the pointer
+	fmt.Fprintln(w, i)  // see the new value of i
 
-	p = &j         // point to j
-	*p = *p / 37   // divide j through the pointer
-	fmt.Println(j) // see the new value of j
+	p = &j             // point to j
+	*p = *p / 37       // divide j through the pointer
+	fmt.Fprintln(w, j) // see the new value of j
 
 	// & generates a pointer based on the operand
 	// * denotes pointer's underlying value
 }
 
-func structsStructure() {
-	// struct is just a collection of fields
+func structsStructure(w io.Writer) {
+	// struct is just a collection of fields. geo.Vec2 has the same shape but
+	// it has a String method, so it would print as (1, 2) instead of {1 2}
 	type Vertex struct {
 		X int
 		Y int
 	}
-	fmt.Println(Vertex{1, 2})
+	fmt.Fprintln(w, Vertex{1, 2})
 
 	v := Vertex{1, 2}
 	v.X = 4
-	fmt.Println(v.X)
+	fmt.Fprintln(w, v.X)
 
 	v2 := Vertex{1, 2}
 	p := &v2
 	// we do not need the * for pointers and struct fields. convenience. not an error
 	p.X = 1e9
-	fmt.Println(v2)
+	fmt.Fprintln(w, v2)
 
 	var (
 		v1s = Vertex{1, 2}  // has type Vertex
@@ -63,28 +90,29 @@ func structsStructure() {
 		v3s = Vertex{}      // X:0 and Y:0
 		ps  = &Vertex{1, 2} // has type *Vertex
 	)
-	fmt.Println(v1s, ps, v2s, v3s)
+	fmt.Fprintln(w, v1s, ps, v2s, v3s)
 }
 
-func arraysArrange() {
+func arraysArrange(w io.Writer) {
 	var a [2]string
 	a[0] = "Hello"
 	a[1] = "World"
-	fmt.Println(a[0], a[1])
-	fmt.Println(a)
+	fmt.Fprintln(w, a[0], a[1])
+	fmt.Fprintln(w, a)
 
 	primes := [6]int{2, 3, 5, 7, 11, 13}
-	fmt.Println(primes)
+	fmt.Fprintln(w, primes)
 
 	// arrays cannot be resized in go but slices can
 }
 
-func slicesSlice() {
+func slicesSlice(env *lesson.Env) {
+	w := env.Out
 	primes := [6]int{2, 3, 5, 7, 11, 13}
 
 	// a slice of elements 1-3 from primes
 	var s []int = primes[1:4]
-	fmt.Println(s)
+	fmt.Fprintln(w, s)
 
 	// a slice is a flexible view into the elements of any array
 	// slices do not store data. they are just references
@@ -96,15 +124,19 @@ func slicesSlice() {
 		"George",
 		"Ringo",
 	}
-	fmt.Println(names)
+	fmt.Fprintln(w, names)
 
 	a := names[0:2]
 	b := names[1:3]
-	fmt.Println(a, b)
+	fmt.Fprintln(w, a, b)
 
 	b[0] = "XXX"
-	fmt.Println(a, b)
-	fmt.Println(names)
+	fmt.Fprintln(w, a, b)
+	fmt.Fprintln(w, names)
+	if env.Verbose {
+		// one array, three views of it. that's why a saw the XXX
+		sliceview.Fprint(w, sliceview.Of("names", names[:]), sliceview.Of("a", a), sliceview.Of("b", b))
+	}
 
 	/*
 		A slice literal is like an array literal without the length.
@@ -114,10 +146,10 @@ func slicesSlice() {
 		[]bool{true, true, false}
 	*/
 	q := []int{2, 3, 5, 7, 11, 13}
-	fmt.Println(q)
+	fmt.Fprintln(w, q)
 
 	r := []bool{true, false, true, true, false, true}
-	fmt.Println(r)
+	fmt.Fprintln(w, r)
 
 	sliteral := []struct {
 		i int
@@ -130,67 +162,78 @@ func slicesSlice() {
 		{11, false},
 		{13, true},
 	}
-	fmt.Println(sliteral)
+	fmt.Fprintln(w, sliteral)
 
 	sb := []int{2, 3, 5, 7, 11, 13}
 	sb = sb[1:4]
-	fmt.Println(sb)
+	fmt.Fprintln(w, sb)
 	sb = sb[:2] // ie sb[0:2]
-	fmt.Println(sb)
+	fmt.Fprintln(w, sb)
 	sb = sb[1:] // ie sb[1:2]
-	fmt.Println(sb)
+	fmt.Fprintln(w, sb)
 }
 
-func slicesLenCap() {
+func slicesLenCap(env *lesson.Env) {
 	// slice length = number of elements
 	// slice capacity = number of elements of slice's array, counting from first element in slice
 	s := []int{2, 3, 5, 7, 11, 13}
-	printSlice(s)
+	printSlice(env, s)
 
 	// Slice the slice to give it zero length.
 	s = s[:0]
-	printSlice(s)
+	printSlice(env, s)
 
 	// Extend its length.
 	s = s[:4]
-	printSlice(s)
+	printSlice(env, s)
 
 	// Drop its first two values.
 	s = s[2:]
-	printSlice(s)
+	printSlice(env, s)
 
 	// zero value (default) of slice is nil
 }
 
-func printSlice(s []int) {
-	fmt.Printf("len=%d cap=%d %v\n", len(s), cap(s), s)
+// with --verbose these also draw the array behind the slice
+func printSlice(env *lesson.Env, s []int) {
+	fmt.Fprintf(env.Out, "len=%d cap=%d %v\n", len(s), cap(s), s)
+	if env.Verbose {
+		sliceview.Fprint(env.Out, sliceview.Of("s", s))
+	}
 }
 
-func slicesMake() {
+func slicesMake(env *lesson.Env) {
 	/*
 		Slices can be created with the built-in make function; this is how you create dynamically-sized arrays.
 		The make function allocates a zeroed array and returns a slice that refers to that array:
 		To specify a capacity, pass a third argument to make:
 	*/
 	a := make([]int, 5)
-	printSliceOther("a", a)
+	printSliceOther(env, "a", a)
 
 	b := make([]int, 0, 5)
-	printSliceOther("b", b)
+	printSliceOther(env, "b", b)
 
 	c := b[:2]
-	printSliceOther("c", c)
+	printSliceOther(env, "c", c)
 
 	d := c[2:5]
-	printSliceOther("d", d)
+	printSliceOther(env, "d", d)
+	if env.Verbose {
+		// b, c and d all point into the same 5 ints. a has its own
+		sliceview.Fprint(env.Out, sliceview.Of("a", a), sliceview.Of("b", b), sliceview.Of("c", c), sliceview.Of("d", d))
+	}
 }
 
-func printSliceOther(s string, x []int) {
-	fmt.Printf("%s len=%d cap=%d %v\n",
+func printSliceOther(env *lesson.Env, s string, x []int) {
+	fmt.Fprintf(env.Out, "%s len=%d cap=%d %v\n",
 		s, len(x), cap(x), x)
+	if env.Verbose {
+		sliceview.Fprint(env.Out, sliceview.Of(s, x))
+	}
 }
 
-func sliceTacToe() {
+func sliceTacToe(w io.Writer) {
 	// you can have a slice of a slice
 	// Create a tic-tac-toe board.
 	board := [][]string{
@@ -207,32 +250,96 @@ func sliceTacToe() {
 	board[0][2] = "X"
 
 	for i := 0; i < len(board); i++ {
-		fmt.Printf("%s\n", strings.Join(board[i], " "))
+		fmt.Fprintf(w, "%s\n", strings.Join(board[i], " "))
 	}
 }
 
-func sliceAppend() {
+// the tictactoe package keeps the board in one flat slice instead, cell r*n+c,
+// and knows the rules. play it with $ go run . tictactoe
+func ticTacToeAI(w io.Writer) {
+	b, _ := tictactoe.New(3, 3)
+	for _, m := range []tictactoe.Move{{Row: 0, Col: 0}, {Row: 2, Col: 2}, {Row: 1, Col: 2}, {Row: 1, Col: 0}, {Row: 0, Col: 2}} {
+		b.Play(m)
+	}
+	fmt.Fprint(w, b)
+
+	// let the computer play both sides from here. it searches every way the
+	// game could go so it never loses, which makes it a draw
+	var ai tictactoe.AI
+	for b.State() == tictactoe.InProgress {
+		m, _ := ai.BestMove(b)
+		fmt.Fprintln(w, b.Turn(), "plays", m.Row, m.Col)
+		b.Play(m)
+	}
+	fmt.Fprint(w, b)
+	fmt.Fprintln(w, b.State() == tictactoe.Draw, b.Play(tictactoe.Move{Row: 0, Col: 0}))
+}
+
+func sliceAppend(env *lesson.Env) {
 	// note s is a slice not an array. arrays have fixed size [0, [1], etc.
 	var s []int
-	printSlice(s)
+	printSlice(env, s)
 
 	// append works on nil slices.
+	old := s
 	s = append(s, 0)
-	printSlice(s)
+	printSlice(env, s)
+	reallocated(env, old, s)
 
 	// The slice grows as needed.
+	old = s
 	s = append(s, 1)
-	printSlice(s)
+	printSlice(env, s)
+	reallocated(env, old, s)
 
 	// We can add more than one element at a time.
+	old = s
 	s = append(s, 2, 3, 4)
-	printSlice(s)
+	printSlice(env, s)
+	reallocated(env, old, s)
+
+	// cap=6 rather than 5 because of how append rounds up. see the growth
+	// package, or $ go run . growth --n 1000
+}
+
+// when append runs out of cap it makes a bigger array and copies into it.
+// the old slice keeps pointing at the old array
+func reallocated(env *lesson.Env, old, s []int) {
+	if env.Verbose {
+		fmt.Fprintln(env.Out, "append reallocated:", sliceview.Reallocated(old, s))
+	}
 }
 
-func rangeSimp() {
+// sliceview draws the slice headers and the arrays they point into. the
+// arrays get letters since their addresses change every run
+func sliceAliasing(w io.Writer) {
+	names := [4]string{"John", "Paul", "George", "Ringo"}
+	a := names[0:2]
+	b := names[1:3]
+	b[0] = "XXX"
+	sliceview.Fprint(w, sliceview.Of("names", names[:]), sliceview.Of("a", a), sliceview.Of("b", b))
+	fmt.Fprintln(w, sliceview.SameArray(a, b))
+
+	// there's room after a, so append writes straight over George
+	a2 := append(a, "Pete")
+	fmt.Fprintln(w, names, sliceview.Reallocated(a, a2))
+
+	// b has one spare slot, this needs two. so b2 gets a new array and
+	// stops seeing changes to names
+	b2 := append(b, "Stuart", "Pete")
+	fmt.Fprintln(w, sliceview.Reallocated(b, b2), sliceview.SameArray(b, b2))
+
+	// a full slice expression s[lo:hi:max] caps a at 2, so append has to copy
+	// and can't clobber anything
+	c := names[0:2:2]
+	var none []string
+	sliceview.Fprint(w, sliceview.Of("a2", a2), sliceview.Of("b2", b2), sliceview.Of("c", c), sliceview.Of("none", none))
+}
+
+func rangeSimp(w io.Writer) {
 	var pow = []int{1, 2, 4, 8, 16, 32, 64, 128}
 	for idx, val := range pow {
-		fmt.Printf("2**%d = %d\n", idx, val)
+		fmt.Fprintf(w, "2**%d = %d\n", idx, val)
 	}
 
 	// range works for slices and maps
@@ -243,71 +350,159 @@ func rangeSimp() {
 		pow2[i] = 1 << uint(i) // == 2**i
 	}
 	for _, value := range pow2 {
-		fmt.Printf("%d\n", value)
+		fmt.Fprintf(w, "%d\n", value)
 	}
 }
 
-func mapsMap() {
+func mapsMap(w io.Writer) {
 	// maps map keys and values. same as in other languages. aka dictionary
 	// maps can be nil. nil maps have no keys and cannot get keys added
 
-	type Vertex struct {
-		Lat, Long float64
+	// the value type is a struct of two float64s, Lat and Lng
+	var m map[string]geo.LatLng
+
+	m = make(map[string]geo.LatLng)
+	m["Bell Labs"] = geo.LatLng{Lat: 40.68433, Lng: -74.39967}
+	fmt.Fprintln(w, m["Bell Labs"])
+
+	// map literals are like struct literals but keys are required
+	var mlit = map[string]geo.LatLng{
+		"Bell Labs": geo.LatLng{Lat: 40.68433, Lng: -74.39967},
+		"Google":    geo.LatLng{Lat: 37.42202, Lng: -122.08408},
 	}
+	fmt.Fprintln(w, mlit)
+}
 
-	var m map[string]Vertex
+// mapsMap's coordinates can't do anything by themselves. geo.Places is a map
+// from names to places underneath, with searching by distance on top
+func mapsDistance(w io.Writer) {
+	places, err := geo.LoadCSV(strings.NewReader(`name,lat,lng
+Bell Labs,40.68433,-74.39967
+Google,37.42202,-122.08408
+Empire State Building,40.74844,-73.98566
+Golden Gate Bridge,37.81993,-122.47826
+`))
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
 
-	m = make(map[string]Vertex)
-	m["Bell Labs"] = Vertex{
-		40.68433, -74.39967,
+	bell, _ := places.Get("Bell Labs")
+	google, _ := places.Get("Google")
+	km, _ := places.Distance("Bell Labs", "Google")
+	fmt.Fprintf(w, "Bell Labs to Google is %.1f km as the crow flies\n", km)
+	// the earth isn't quite a sphere. vincenty uses the proper shape
+	if km, err := bell.Vincenty(google); err == nil {
+		fmt.Fprintf(w, "or %.1f km on the WGS-84 ellipsoid\n", km)
 	}
-	fmt.Println(m["Bell Labs"])
+	fmt.Fprintf(w, "head off on a bearing of %.1f degrees\n", bell.Bearing(google))
+	mid := bell.Midpoint(google)
+	fmt.Fprintf(w, "halfway is around %.2f,%.2f\n", mid.Lat, mid.Lng)
 
-	// map literals are like struct literals but keys are required
-	var mlit = map[string]Vertex{
-		"Bell Labs": Vertex{
-			40.68433, -74.39967,
-		},
-		"Google": Vertex{
-			37.42202, -122.08408,
-		},
+	for _, m := range places.Within(bell, 100) {
+		fmt.Fprintf(w, "within 100 km of Bell Labs: %s (%.1f km)\n", m.Name, m.Km)
 	}
-	fmt.Println(mlit)
+	nearest := places.Nearest(geo.LatLng{Lat: 37.7749, Lng: -122.4194}, 1)[0] // san francisco
+	fmt.Fprintf(w, "nearest to San Francisco: %s\n", nearest.Name)
 }
 
-func mapsMutate() {
+func mapsMutate(w io.Writer) {
 	m := make(map[string]int)
 
 	m["Answer"] = 42
-	fmt.Println("The value:", m["Answer"])
+	fmt.Fprintln(w, "The value:", m["Answer"])
 
 	m["Answer"] = 48
-	fmt.Println("The value:", m["Answer"])
+	fmt.Fprintln(w, "The value:", m["Answer"])
 
 	delete(m, "Answer")
-	fmt.Println("The value:", m["Answer"])
+	fmt.Fprintln(w, "The value:", m["Answer"])
 
 	v, ok := m["Answer"] // test keys are present with two value assignment
-	fmt.Println("The value:", v, "Present?", ok)
+	fmt.Fprintln(w, "The value:", v, "Present?", ok)
 }
 
-func functionValues() {
+// the classic map exercise. m[word]++ works even the first time a word turns
+// up because a missing key reads as 0. wordcount does the same thing with
+// rules for what a word is. strings.Fields would make "hello 世界" two words
+// as well, but only because of the space
+func mapsWordCount(w io.Writer) {
+	m := make(map[string]int)
+	for _, word := range strings.Fields("the cat sat on the mat") {
+		m[word]++
+	}
+	fmt.Fprintln(w, m)
+
+	c, _ := wordcount.Count(strings.NewReader("chinese or japanese characters. hello 世界\nHello, 世界!"))
+	fmt.Fprintln(w, c.Words, c.Freq)
+	fmt.Fprintln(w, c.Top(2), c.TopBigrams(1))
+}
+
+// a plain map can't be written from two goroutines at once. safemap puts a
+// lock around it. m["hits"]++ is a read then a write, so even with a lock
+// another goroutine can sneak in between. CompareAndSwap only writes if
+// nobody changed the value since we read it, otherwise we go round again
+func mapsConcurrent(w io.Writer) {
+	m := safemap.NewSharded[string, int](0)
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				for {
+					n, _ := m.LoadOrStore("hits", 0)
+					if m.CompareAndSwap("hits", n, n+1) {
+						break
+					}
+				}
+			}
+			m.Store(fmt.Sprint("worker", i), 100)
+		}()
+	}
+	wg.Wait()
+
+	v, ok := m.Load("hits")
+	fmt.Fprintln(w, "The value:", v, "Present?", ok, "Keys:", m.Len())
+	m.Delete("hits")
+	snap := m.Snapshot()
+	fmt.Fprintln(w, len(snap), snap["worker3"])
+}
+
+func functionValues(w io.Writer) {
 	// in go functions are values and can be passed around as such
 	// functions can be arguments or return values
 	hypot := func(x, y float64) float64 {
 		return math.Sqrt(x*x + y*y)
 	}
-	fmt.Println(hypot(5, 12))
+	fmt.Fprintln(w, hypot(5, 12))
 
-	fmt.Println(compute(hypot))    // ie the hypotenuse of 3 and 4
-	fmt.Println(compute(math.Pow)) // ie 3 to the 4th power
+	fmt.Fprintln(w, compute(hypot))    // ie the hypotenuse of 3 and 4
+	fmt.Fprintln(w, compute(math.Pow)) // ie 3 to the 4th power
 }
 
 func compute(fn func(float64, float64) float64) float64 {
 	return fn(3, 4)
 }
 
-func functionClosures() {
+// calc keeps its operators in a map of the same func(float64, float64) float64
+// compute takes. ^ is math.Pow and hypot is math.Hypot, nothing wrapped
+func functionCalc(w io.Writer) {
+	c := calc.New()
+	for _, line := range []string{"hypot(5, 12)", "3^4", "1 + 2 * -3", "2^3^2", "x = hypot(3, 4)", "x * pi", "1 +* 2", "y"} {
+		v, err := c.Eval(line)
+		if err != nil {
+			fmt.Fprintf(w, "%-16s error at %v\n", line, err)
+			continue
+		}
+		fmt.Fprintf(w, "%-16s %g\n", line, v)
+	}
+	// how the parser grouped it
+	n, _ := calc.Parse("1 + 2 * -3 ^ 2")
+	fmt.Fprintln(w, n)
+}
+
+func functionClosures(w io.Writer) {
 	/*
 		Go functions may be closures. A closure is a function value that references variables from outside its body.
 		The function may access and assign to the referenced variables; in this sense the function is "bound" to the variables.
@@ -315,7 +510,7 @@ func functionClosures() {
 	*/
 	pos, neg := adder(), adder()
 	for i := 0; i < 10; i++ {
-		fmt.Println(
+		fmt.Fprintln(w,
 			pos(i),
 			neg(-2*i),
 		)
@@ -329,3 +524,67 @@ func adder() func(int) int {
 		return sum
 	}
 }
+
+// the closures package is built out of functions like adder. each call hands
+// back a closure with its own variables, so like pos and neg nothing is shared
+func closureToolbox(w io.Writer) {
+	f, g := closures.Fibonacci(), closures.Fibonacci()
+	for range 5 {
+		f()
+	}
+	fmt.Fprintln(w, f(), g()) // f is 5 in, g is just starting
+
+	meanA, meanB := closures.Mean(), closures.Mean()
+	variance, minmax := closures.Variance(), closures.MinMax()
+	for _, x := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		meanA(x)
+		variance(x)
+		minmax(x)
+	}
+	lo, hi := minmax(5)
+	fmt.Fprintln(w, meanA(5), meanB(5), variance(5), lo, hi)
+
+	// memoizing a recursive function. fib calls the memoized version so each
+	// n is only ever worked out once
+	calls := 0
+	var fib func(int) int
+	fib = closures.Memoize(func(n int) int {
+		calls++
+		if n < 2 {
+			return n
+		}
+		return fib(n-1) + fib(n-2)
+	})
+	fmt.Fprintln(w, fib(50), calls)
+
+	// compute wants func(float64, float64) float64. Curry2 takes them one at
+	// a time, so we can fix the first argument
+	powOf2 := closures.Curry2(math.Pow)(2)
+	half := func(x float64) float64 { return x / 2 }
+	fmt.Fprintln(w, powOf2(10), closures.Compose(powOf2, half)(10), closures.Pipe(half, half, powOf2)(12))
+
+	// a fake clock so we can say exactly when things happen
+	c := clock.NewFake(clock.Playground)
+	saves := 0
+	save := closures.Throttle(c, time.Second, func() { saves++ })
+	for range 5 {
+		fmt.Fprint(w, save(), " ")
+		c.Advance(300 * time.Millisecond)
+	}
+	fmt.Fprintln(w, saves)
+
+	// three keypresses half a second apart are one burst, so f runs once
+	var runs atomic.Int32
+	done := make(chan bool)
+	typed := closures.Debounce(c, time.Second, func() {
+		runs.Add(1)
+		done <- true
+	})
+	for range 3 {
+		typed()
+		c.Advance(500 * time.Millisecond)
+	}
+	c.Advance(time.Second)
+	<-done
+	fmt.Fprintln(w, "debounced", runs.Load())
+}
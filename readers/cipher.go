@@ -0,0 +1,84 @@
+package readers
+
+import (
+	"fmt"
+	"io"
+)
+
+// shift moves a letter n places round the alphabet keeping its case.
+// anything else stays put
+func shift(b byte, n int) byte {
+	var base byte
+	switch {
+	case 'a' <= b && b <= 'z':
+		base = 'a'
+	case 'A' <= b && b <= 'Z':
+		base = 'A'
+	default:
+		return b
+	}
+	// ((x % 26) + 26) % 26 keeps it positive when n is negative
+	return base + byte(((int(b-base)+n)%26+26)%26)
+}
+
+func isLetter(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// Caesar shifts every letter n places. Caesar(-n) undoes Caesar(n)
+func Caesar(n int) Transform {
+	return func(b byte) byte { return shift(b, n) }
+}
+
+// Rot13 is Caesar(13). there are 26 letters so doing it twice gets you back
+// where you started, it undoes itself
+func Rot13() Transform {
+	return Caesar(13)
+}
+
+// Vigenere shifts each letter by the next letter of key, a=0 b=1 and so on,
+// going round the key again when it runs out. only letters use up the key.
+// decrypt shifts the other way
+func Vigenere(key string, decrypt bool) (Transform, error) {
+	var shifts []int
+	for _, r := range key {
+		switch {
+		case 'a' <= r && r <= 'z':
+			shifts = append(shifts, int(r-'a'))
+		case 'A' <= r && r <= 'Z':
+			shifts = append(shifts, int(r-'A'))
+		default:
+			return nil, fmt.Errorf("readers: vigenère key %q can only have letters a to z", key)
+		}
+	}
+	if len(shifts) == 0 {
+		return nil, fmt.Errorf("readers: vigenère needs a key")
+	}
+	sign := 1
+	if decrypt {
+		sign = -1
+	}
+	i := 0
+	return func(b byte) byte {
+		if !isLetter(b) {
+			return b
+		}
+		out := shift(b, sign*shifts[i])
+		i = (i + 1) % len(shifts)
+		return out
+	}, nil
+}
+
+// Rot13Reader is the tour's exercise. it's NewReader(r, Rot13()) spelled out
+// the long way
+type Rot13Reader struct {
+	R io.Reader
+}
+
+func (r Rot13Reader) Read(p []byte) (int, error) {
+	n, err := r.R.Read(p)
+	for i := range p[:n] {
+		p[i] = shift(p[i], 13)
+	}
+	return n, err
+}
@@ -0,0 +1,191 @@
+package readers
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/clock"
+)
+
+// ChunkReader hands back at most n bytes per Read however big p is. handy
+// for checking that whatever reads it copes with short reads, which any
+// reader is allowed to do. N <= 0 means no limit, so the zero value just
+// passes reads through
+type ChunkReader struct {
+	R io.Reader
+	N int
+}
+
+func (r *ChunkReader) Read(p []byte) (int, error) {
+	if r.N > 0 && len(p) > r.N {
+		p = p[:r.N]
+	}
+	return r.R.Read(p)
+}
+
+// ChunkWriter passes writes on to W in pieces of at most N bytes. N <= 0
+// means no limit, the same as ChunkReader
+type ChunkWriter struct {
+	W io.Writer
+	N int
+}
+
+func (w *ChunkWriter) Write(p []byte) (int, error) {
+	size := w.N
+	if size <= 0 {
+		size = len(p)
+	}
+	written := 0
+	for len(p) > 0 {
+		n, err := w.W.Write(p[:min(len(p), size)])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		if n == 0 {
+			// a Writer that writes nothing has to say why. don't spin on it
+			return written, io.ErrShortWrite
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// limiter keeps a byte count to rate bytes a second. it doesn't need a
+// ticker, it works out when the bytes so far are due and sleeps until then
+type limiter struct {
+	rate  int
+	clock clock.Clock
+	start time.Time
+	sent  int64
+}
+
+func (l *limiter) burst() int {
+	// a tenth of a second at a time, so it trickles rather than lurches
+	return max(1, l.rate/10)
+}
+
+func (l *limiter) wait(n int) {
+	if l.start.IsZero() {
+		l.start = l.clock.Now()
+	}
+	l.sent += int64(n)
+	due := l.start.Add(time.Duration(l.sent) * time.Second / time.Duration(l.rate))
+	if d := due.Sub(l.clock.Now()); d > 0 {
+		l.clock.Sleep(d)
+	}
+}
+
+// RateReader reads at most rate bytes a second
+type RateReader struct {
+	r io.Reader
+	l limiter
+}
+
+// NewRateReader limits r to rate bytes a second going by c
+func NewRateReader(r io.Reader, rate int, c clock.Clock) *RateReader {
+	return &RateReader{r: r, l: limiter{rate: max(rate, 1), clock: c}}
+}
+
+func (r *RateReader) Read(p []byte) (int, error) {
+	if b := r.l.burst(); len(p) > b {
+		p = p[:b]
+	}
+	n, err := r.r.Read(p)
+	r.l.wait(n)
+	return n, err
+}
+
+// RateWriter writes at most rate bytes a second
+type RateWriter struct {
+	w io.Writer
+	l limiter
+}
+
+// NewRateWriter limits w to rate bytes a second going by c
+func NewRateWriter(w io.Writer, rate int, c clock.Clock) *RateWriter {
+	return &RateWriter{w: w, l: limiter{rate: max(rate, 1), clock: c}}
+}
+
+func (w *RateWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n, err := w.w.Write(p[:min(len(p), w.l.burst())])
+		written += n
+		w.l.wait(n)
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// numberer puts a line number in front of every line. a line can be split
+// over many reads, so it has to remember whether the next byte starts one
+type numberer struct {
+	line    int
+	midLine bool
+}
+
+func (n *numberer) add(dst, src []byte) []byte {
+	for _, b := range src {
+		if !n.midLine {
+			n.line++
+			dst = fmt.Appendf(dst, "%6d  ", n.line)
+			n.midLine = true
+		}
+		dst = append(dst, b)
+		if b == '\n' {
+			n.midLine = false
+		}
+	}
+	return dst
+}
+
+// LineReader numbers the lines read from R, like cat -n
+type LineReader struct {
+	R io.Reader
+
+	n       numberer
+	pending []byte // numbered but not read yet
+	err     error  // from R, returned once pending is empty
+	buf     []byte
+}
+
+func (r *LineReader) Read(p []byte) (int, error) {
+	// adding numbers makes the output longer than what came in, so it
+	// can't be done in place. keep what doesn't fit for next time
+	for len(r.pending) == 0 && r.err == nil {
+		if r.buf == nil {
+			r.buf = make([]byte, 4096)
+		}
+		var n int
+		n, r.err = r.R.Read(r.buf)
+		r.pending = r.n.add(r.pending, r.buf[:n])
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	if len(r.pending) == 0 {
+		return n, r.err
+	}
+	return n, nil
+}
+
+// LineWriter numbers the lines written through it
+type LineWriter struct {
+	W io.Writer
+
+	n   numberer
+	buf []byte
+}
+
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.buf = w.n.add(w.buf[:0], p)
+	if _, err := w.W.Write(w.buf); err != nil {
+		// can't tell how much of p made it once numbers are mixed in
+		return 0, err
+	}
+	return len(p), nil
+}
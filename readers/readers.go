@@ -0,0 +1,89 @@
+// Package readers wraps io.Readers in other io.Readers, the thing readersRead
+// leads up to. a wrapper's Read calls the Read underneath and changes what
+// comes back, so wrappers stack: Upper(Rot13(f)) reads f, undoes the rot13 and
+// shouts it. each one has a Writer twin that does the same on the way out
+package readers
+
+import (
+	"io"
+)
+
+// Transform changes one byte at a time. it can keep state between calls,
+// the Vigenère cipher needs to know where it is in the key
+type Transform func(b byte) byte
+
+// Reader runs everything read from R through T
+type Reader struct {
+	R io.Reader
+	T Transform
+}
+
+// NewReader wraps r
+func NewReader(r io.Reader, t Transform) *Reader {
+	return &Reader{R: r, T: t}
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.R.Read(p)
+	// the bytes come before the error. a reader can return data and io.EOF
+	// from the same call
+	for i := range p[:n] {
+		p[i] = r.T(p[i])
+	}
+	return n, err
+}
+
+// Writer runs everything written through T before passing it to W
+type Writer struct {
+	W io.Writer
+	T Transform
+
+	buf []byte
+}
+
+// NewWriter wraps w
+func NewWriter(w io.Writer, t Transform) *Writer {
+	return &Writer{W: w, T: t}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	// Write mustn't change p, the caller still owns it. so work on a copy
+	w.buf = append(w.buf[:0], p...)
+	for i := range w.buf {
+		w.buf[i] = w.T(w.buf[i])
+	}
+	return w.W.Write(w.buf)
+}
+
+// Upper makes ASCII letters upper case. it works a byte at a time, so other
+// letters like é pass through unchanged rather than risk cutting a rune that
+// is split across two reads
+func Upper() Transform {
+	return func(b byte) byte {
+		if 'a' <= b && b <= 'z' {
+			return b - 'a' + 'A'
+		}
+		return b
+	}
+}
+
+// Lower is Upper the other way
+func Lower() Transform {
+	return func(b byte) byte {
+		if 'A' <= b && b <= 'Z' {
+			return b - 'A' + 'a'
+		}
+		return b
+	}
+}
+
+// MyReader is the tour's exercise, a stream of 'A' that never ends. wrap it
+// in io.LimitReader to get some of it
+type MyReader struct{}
+
+func (MyReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'A'
+	}
+	return len(p), nil
+}
@@ -0,0 +1,129 @@
+package readers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/clock"
+)
+
+const text = "Lbh penpxrq gur pbqr!\nand a second line\nno newline"
+
+// each reader wrapped around text, and what reading it all should give
+var wrapped = []struct {
+	name string
+	wrap func(io.Reader) io.Reader
+	want string
+}{
+	{"Upper", func(r io.Reader) io.Reader { return NewReader(r, Upper()) }, strings.ToUpper(text)},
+	{"Rot13", func(r io.Reader) io.Reader { return NewReader(r, Rot13()) }, "You cracked the code!\nnaq n frpbaq yvar\nab arjyvar"},
+	{"Rot13Reader", func(r io.Reader) io.Reader { return Rot13Reader{r} }, "You cracked the code!\nnaq n frpbaq yvar\nab arjyvar"},
+	{"ChunkReader", func(r io.Reader) io.Reader { return &ChunkReader{R: r, N: 3} }, text},
+	{"zero ChunkReader", func(r io.Reader) io.Reader { return &ChunkReader{R: r} }, text},
+	{"LineReader", func(r io.Reader) io.Reader { return &LineReader{R: r} },
+		"     1  Lbh penpxrq gur pbqr!\n     2  and a second line\n     3  no newline"},
+	{"RateReader", func(r io.Reader) io.Reader {
+		return NewRateReader(r, 5, clock.NewFake(time.Time{}))
+	}, text},
+}
+
+func TestReaders(t *testing.T) {
+	for _, tt := range wrapped {
+		for name, under := range map[string]func(io.Reader) io.Reader{
+			"":        func(r io.Reader) io.Reader { return r },
+			"OneByte": iotest.OneByteReader,
+			"Half":    iotest.HalfReader,
+			// N < 0 is no limit, it used to panic
+			"Chunk-1": func(r io.Reader) io.Reader { return &ChunkReader{R: r, N: -1} },
+		} {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				r := tt.wrap(under(strings.NewReader(text)))
+				if err := iotest.TestReader(r, []byte(tt.want)); err != nil {
+					t.Error(err)
+				}
+			})
+		}
+	}
+}
+
+func TestReadersPassErrorsOn(t *testing.T) {
+	boom := errors.New("boom")
+	for _, tt := range wrapped {
+		r := tt.wrap(io.MultiReader(strings.NewReader("ok\n"), iotest.ErrReader(boom)))
+		got, err := io.ReadAll(r)
+		if err != boom {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, boom)
+		}
+		if len(got) == 0 {
+			t.Errorf("%s: lost the bytes read before the error", tt.name)
+		}
+	}
+}
+
+func TestChunkWriter(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, 4, 100} {
+		var b bytes.Buffer
+		w := &ChunkWriter{W: &b, N: n}
+		if got, err := io.WriteString(w, text); got != len(text) || err != nil {
+			t.Errorf("N=%d: wrote %d, %v", n, got, err)
+		}
+		if b.String() != text {
+			t.Errorf("N=%d: got %q", n, b.String())
+		}
+	}
+}
+
+// stuck writes nothing and doesn't say why
+type stuck struct{}
+
+func (stuck) Write([]byte) (int, error) { return 0, nil }
+
+func TestChunkWriterStuck(t *testing.T) {
+	if _, err := (&ChunkWriter{W: stuck{}, N: 2}).Write([]byte("abc")); err != io.ErrShortWrite {
+		t.Errorf("got %v, want io.ErrShortWrite", err)
+	}
+}
+
+func TestWritersMatchReaders(t *testing.T) {
+	for name, w := range map[string]func(io.Writer) io.Writer{
+		"Rot13": func(w io.Writer) io.Writer { return NewWriter(w, Rot13()) },
+		"Line":  func(w io.Writer) io.Writer { return &LineWriter{W: w} },
+	} {
+		var b bytes.Buffer
+		// a byte at a time, so a line is split over many writes
+		cw := &ChunkWriter{W: w(&b), N: 1}
+		io.WriteString(cw, text)
+		var r io.Reader
+		switch name {
+		case "Rot13":
+			r = NewReader(strings.NewReader(text), Rot13())
+		case "Line":
+			r = &LineReader{R: strings.NewReader(text)}
+		}
+		want, _ := io.ReadAll(r)
+		if b.String() != string(want) {
+			t.Errorf("%s: writer gave %q, reader %q", name, b.String(), want)
+		}
+	}
+}
+
+func TestVigenereRoundTrip(t *testing.T) {
+	enc, err := Vigenere("lemon", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, _ := Vigenere("lemon", true)
+	// the key position carries over between reads, so split them up
+	got, _ := io.ReadAll(NewReader(iotest.OneByteReader(NewReader(strings.NewReader(text), enc)), dec))
+	if string(got) != text {
+		t.Errorf("got %q back", got)
+	}
+	if _, err := Vigenere("l3mon", false); err == nil {
+		t.Error("a key with a digit in it was accepted")
+	}
+}
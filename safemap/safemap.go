@@ -0,0 +1,128 @@
+// Package safemap has maps that goroutines can share. a plain map like the
+// one in mapsMutate is fine from one goroutine, but two goroutines writing it
+// at once is a data race and the runtime will often kill the program with
+// "concurrent map writes". the fix is a lock around every access
+package safemap
+
+import (
+	"maps"
+	"sync"
+)
+
+// SafeMap is what both maps here can do. it's the same set of methods as
+// sync.Map but typed, so there's no casting v.(int) everywhere
+type SafeMap[K comparable, V any] interface {
+	Load(k K) (v V, ok bool)
+	Store(k K, v V)
+
+	// LoadOrStore returns the value already there if there is one, or stores
+	// v and returns it. loaded says which happened
+	LoadOrStore(k K, v V) (actual V, loaded bool)
+
+	// CompareAndSwap stores new only if k currently holds old. V must be
+	// comparable at run time or it panics, the same as sync.Map
+	CompareAndSwap(k K, old, new V) (swapped bool)
+
+	Delete(k K)
+
+	// Range calls f for each entry until f returns false. the map can be
+	// changed while ranging, including from inside f
+	Range(f func(k K, v V) bool)
+
+	// Snapshot copies the whole map into a plain one
+	Snapshot() map[K]V
+
+	Len() int
+}
+
+// RWMap is a map with one sync.RWMutex. any number of goroutines can read at
+// once but a write waits for everyone else. the zero value is an empty map
+// ready to use, like sync.Map
+type RWMap[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// New makes an empty RWMap
+func New[K comparable, V any]() *RWMap[K, V] {
+	return &RWMap[K, V]{m: make(map[K]V)}
+}
+
+func (m *RWMap[K, V]) Load(k K) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.m[k]
+	return v, ok
+}
+
+func (m *RWMap[K, V]) Store(k K, v V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+	m.m[k] = v
+}
+
+// init makes the map on the first write. reading a nil map is fine, so only
+// the methods that store need it. m.mu must be held
+func (m *RWMap[K, V]) init() {
+	if m.m == nil {
+		m.m = make(map[K]V)
+	}
+}
+
+func (m *RWMap[K, V]) LoadOrStore(k K, v V) (V, bool) {
+	// most calls find the key, so try with just the read lock first
+	if actual, ok := m.Load(k); ok {
+		return actual, true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// someone may have stored it between the two locks
+	if actual, ok := m.m[k]; ok {
+		return actual, true
+	}
+	m.init()
+	m.m[k] = v
+	return v, false
+}
+
+func (m *RWMap[K, V]) CompareAndSwap(k K, old, new V) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, ok := m.m[k]; !ok || any(cur) != any(old) {
+		return false
+	}
+	m.m[k] = new
+	return true
+}
+
+func (m *RWMap[K, V]) Delete(k K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.m, k)
+}
+
+// Range works on a snapshot, so f can call back into m without deadlocking
+func (m *RWMap[K, V]) Range(f func(k K, v V) bool) {
+	for k, v := range m.Snapshot() {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+func (m *RWMap[K, V]) Snapshot() map[K]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.m == nil {
+		// Clone would give back nil, which the caller couldn't write to
+		return make(map[K]V)
+	}
+	return maps.Clone(m.m)
+}
+
+func (m *RWMap[K, V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.m)
+}
@@ -0,0 +1,170 @@
+package safemap
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+	"testing"
+)
+
+// both maps, each from its constructor and as a zero value
+func makers() map[string]func() SafeMap[string, int] {
+	return map[string]func() SafeMap[string, int]{
+		"RWMap":        func() SafeMap[string, int] { return New[string, int]() },
+		"zero RWMap":   func() SafeMap[string, int] { return new(RWMap[string, int]) },
+		"Sharded":      func() SafeMap[string, int] { return NewSharded[string, int](3) },
+		"zero Sharded": func() SafeMap[string, int] { return new(Sharded[string, int]) },
+	}
+}
+
+func TestSafeMap(t *testing.T) {
+	for name, mk := range makers() {
+		t.Run(name, func(t *testing.T) {
+			m := mk()
+			if _, ok := m.Load("a"); ok {
+				t.Error("Load on an empty map found something")
+			}
+			if n := m.Len(); n != 0 {
+				t.Errorf("empty Len = %d", n)
+			}
+			m.Store("a", 1)
+			if v, ok := m.Load("a"); !ok || v != 1 {
+				t.Errorf("Load(a) = %d, %v after Store(a, 1)", v, ok)
+			}
+			if v, loaded := m.LoadOrStore("a", 2); !loaded || v != 1 {
+				t.Errorf("LoadOrStore(a, 2) = %d, %v, want 1, true", v, loaded)
+			}
+			if v, loaded := m.LoadOrStore("b", 2); loaded || v != 2 {
+				t.Errorf("LoadOrStore(b, 2) = %d, %v, want 2, false", v, loaded)
+			}
+			if m.CompareAndSwap("a", 5, 6) {
+				t.Error("CompareAndSwap with the wrong old value swapped")
+			}
+			if m.CompareAndSwap("c", 0, 1) {
+				t.Error("CompareAndSwap on a missing key swapped")
+			}
+			if !m.CompareAndSwap("a", 1, 3) {
+				t.Error("CompareAndSwap(a, 1, 3) didn't swap")
+			}
+			m.Delete("b")
+			want := map[string]int{"a": 3}
+			if got := m.Snapshot(); !maps.Equal(got, want) {
+				t.Errorf("Snapshot = %v, want %v", got, want)
+			}
+			got := map[string]int{}
+			m.Range(func(k string, v int) bool {
+				got[k] = v
+				// calling back in from f mustn't deadlock
+				m.Delete(k)
+				return true
+			})
+			if !maps.Equal(got, want) {
+				t.Errorf("Range saw %v, want %v", got, want)
+			}
+			if n := m.Len(); n != 0 {
+				t.Errorf("Len = %d after deleting everything", n)
+			}
+		})
+	}
+}
+
+func TestZeroSnapshotIsWritable(t *testing.T) {
+	var m RWMap[string, int]
+	m.Snapshot()["x"] = 1
+}
+
+// run with -race. every goroutine hammers the same few keys
+func TestSafeMapConcurrent(t *testing.T) {
+	const goroutines, rounds = 8, 1000
+	for name, mk := range makers() {
+		t.Run(name, func(t *testing.T) {
+			m := mk()
+			var wg sync.WaitGroup
+			for g := range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range rounds {
+						k := fmt.Sprint(i % 10)
+						m.LoadOrStore(k, 0)
+						for {
+							old, _ := m.Load(k)
+							if m.CompareAndSwap(k, old, old+1) {
+								break
+							}
+						}
+						if i%100 == 0 {
+							m.Store(fmt.Sprint("g", g), i)
+							m.Range(func(string, int) bool { return true })
+							m.Snapshot()
+							m.Len()
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			total := 0
+			for i := range 10 {
+				v, _ := m.Load(fmt.Sprint(i))
+				total += v
+			}
+			if total != goroutines*rounds {
+				t.Errorf("counted %d increments, want %d", total, goroutines*rounds)
+			}
+		})
+	}
+}
+
+// syncMap wraps sync.Map in enough of SafeMap for the benchmarks
+type syncMap struct{ m sync.Map }
+
+func (s *syncMap) Load(k string) (int, bool) {
+	v, ok := s.m.Load(k)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+func (s *syncMap) Store(k string, v int) { s.m.Store(k, v) }
+
+type benchMap interface {
+	Load(k string) (int, bool)
+	Store(k string, v int)
+}
+
+var keys = func() []string {
+	k := make([]string, 1024)
+	for i := range k {
+		k[i] = fmt.Sprint("key", i)
+	}
+	return k
+}()
+
+// bench mixes reads and writes, one write to every reads reads, from as many
+// goroutines as -cpu says
+func bench(b *testing.B, m benchMap) {
+	for _, reads := range []int{1, 9, 99} {
+		b.Run(fmt.Sprintf("%d:1", reads), func(b *testing.B) {
+			for _, k := range keys {
+				m.Store(k, 0)
+			}
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					k := keys[i%len(keys)]
+					if i%(reads+1) == 0 {
+						m.Store(k, i)
+					} else {
+						m.Load(k)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkRWMap(b *testing.B)   { bench(b, New[string, int]()) }
+func BenchmarkSharded(b *testing.B) { bench(b, NewSharded[string, int](0)) }
+func BenchmarkSyncMap(b *testing.B) { bench(b, &syncMap{}) }
@@ -0,0 +1,102 @@
+package safemap
+
+import (
+	"hash/maphash"
+	"maps"
+	"runtime"
+	"sync"
+)
+
+// Sharded splits the keys over several RWMaps by hash. goroutines working on
+// keys in different shards never wait for each other, which helps a lot when
+// there are many writers. one lock is a queue for all of them. the zero value
+// is ready to use and gets the default number of shards
+type Sharded[K comparable, V any] struct {
+	once   sync.Once
+	seed   maphash.Seed
+	shards []RWMap[K, V]
+}
+
+// NewSharded makes a map with n shards. n <= 0 picks a few per CPU
+func NewSharded[K comparable, V any](n int) *Sharded[K, V] {
+	m := &Sharded[K, V]{}
+	m.once.Do(func() { m.init(n) })
+	return m
+}
+
+func (m *Sharded[K, V]) init(n int) {
+	if n <= 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+	m.seed = maphash.MakeSeed()
+	m.shards = make([]RWMap[K, V], n)
+}
+
+// all sets up a zero Sharded the first time it's used. everything that
+// touches m.shards goes through here
+func (m *Sharded[K, V]) all() []RWMap[K, V] {
+	m.once.Do(func() { m.init(0) })
+	return m.shards
+}
+
+func (m *Sharded[K, V]) shard(k K) *RWMap[K, V] {
+	shards := m.all()
+	return &shards[maphash.Comparable(m.seed, k)%uint64(len(shards))]
+}
+
+func (m *Sharded[K, V]) Load(k K) (V, bool) { return m.shard(k).Load(k) }
+
+func (m *Sharded[K, V]) Store(k K, v V) { m.shard(k).Store(k, v) }
+
+func (m *Sharded[K, V]) LoadOrStore(k K, v V) (V, bool) { return m.shard(k).LoadOrStore(k, v) }
+
+func (m *Sharded[K, V]) CompareAndSwap(k K, old, new V) bool {
+	return m.shard(k).CompareAndSwap(k, old, new)
+}
+
+func (m *Sharded[K, V]) Delete(k K) { m.shard(k).Delete(k) }
+
+// Range goes a shard at a time, so unlike Snapshot it never sees the whole
+// map at one instant
+func (m *Sharded[K, V]) Range(f func(k K, v V) bool) {
+	shards := m.all()
+	for i := range shards {
+		for k, v := range shards[i].Snapshot() {
+			if !f(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Snapshot locks every shard before copying so the copy is consistent
+func (m *Sharded[K, V]) Snapshot() map[K]V {
+	shards := m.all()
+	for i := range shards {
+		shards[i].mu.RLock()
+	}
+	defer func() {
+		for i := range shards {
+			shards[i].mu.RUnlock()
+		}
+	}()
+	out := make(map[K]V)
+	for i := range shards {
+		maps.Copy(out, shards[i].m)
+	}
+	return out
+}
+
+func (m *Sharded[K, V]) Len() int {
+	n := 0
+	shards := m.all()
+	for i := range shards {
+		n += shards[i].Len()
+	}
+	return n
+}
+
+var (
+	_ SafeMap[string, int] = (*RWMap[string, int])(nil)
+	_ SafeMap[string, int] = (*Sharded[string, int])(nil)
+)
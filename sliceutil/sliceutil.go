@@ -0,0 +1,187 @@
+// Package sliceutil is a companion to Index from the methods lesson, which
+// now just calls the Index here. the same type parameter trick gives you a whole toolbox of slice functions that work
+// for any element type. the standard library slices package has some of these
+// too, this is the write-it-yourself version
+package sliceutil
+
+import "cmp"
+
+// Index returns the index of the first x in s, or -1 if not found
+func Index[T comparable](s []T, x T) int {
+	for i, v := range s {
+		if v == x {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastIndex returns the index of the last x in s, or -1 if not found
+func LastIndex[T comparable](s []T, x T) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == x {
+			return i
+		}
+	}
+	return -1
+}
+
+// IndexFunc returns the index of the first element f is true for, or -1.
+// it only needs any instead of comparable since f does the comparing
+func IndexFunc[T any](s []T, f func(T) bool) int {
+	for i, v := range s {
+		if f(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains reports whether x is in s
+func Contains[T comparable](s []T, x T) bool {
+	return Index(s, x) >= 0
+}
+
+// Map returns a new slice holding f applied to every element of s.
+// note there are two type parameters. T goes in and U comes out
+func Map[T, U any](s []T, f func(T) U) []U {
+	out := make([]U, len(s))
+	for i, v := range s {
+		out[i] = f(v)
+	}
+	return out
+}
+
+// Filter returns a new slice holding the elements f is true for
+func Filter[T any](s []T, f func(T) bool) []T {
+	var out []T
+	for _, v := range s {
+		if f(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Reduce folds s into a single value, starting from init. summing a slice is
+// Reduce(s, 0, func(acc, v int) int { return acc + v })
+func Reduce[T, A any](s []T, init A, f func(A, T) A) A {
+	acc := init
+	for _, v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// Chunk splits s into pieces of n elements. the last one may be shorter.
+// the chunks share s's backing array but have their capacity capped, so
+// appending to one can't overwrite the next. it panics if n < 1
+func Chunk[T any](s []T, n int) [][]T {
+	if n < 1 {
+		panic("sliceutil: Chunk size must be at least 1")
+	}
+	chunks := make([][]T, 0, (len(s)+n-1)/n)
+	for i := 0; i < len(s); i += n {
+		end := min(i+n, len(s))
+		chunks = append(chunks, s[i:end:end])
+	}
+	return chunks
+}
+
+// Partition splits s into the elements f is true for and the rest,
+// keeping their order
+func Partition[T any](s []T, f func(T) bool) (yes, no []T) {
+	for _, v := range s {
+		if f(v) {
+			yes = append(yes, v)
+		} else {
+			no = append(no, v)
+		}
+	}
+	return yes, no
+}
+
+// Uniq returns the elements of s with duplicates removed. the first
+// occurrence of each value wins
+func Uniq[T comparable](s []T) []T {
+	seen := make(map[T]bool, len(s))
+	var out []T
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// GroupBy buckets the elements of s by key. each bucket keeps the order
+// elements appeared in s
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, v := range s {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// the rest need cmp.Ordered, ie. anything that works with < such as ints,
+// floats and strings
+
+// Min returns the smallest element. ok is false if s is empty
+func Min[T cmp.Ordered](s []T) (m T, ok bool) {
+	if len(s) == 0 {
+		return m, false
+	}
+	m = s[0]
+	for _, v := range s[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m, true
+}
+
+// Max returns the largest element. ok is false if s is empty
+func Max[T cmp.Ordered](s []T) (m T, ok bool) {
+	if len(s) == 0 {
+		return m, false
+	}
+	m = s[0]
+	for _, v := range s[1:] {
+		if v > m {
+			m = v
+		}
+	}
+	return m, true
+}
+
+// BinarySearch looks for x in the sorted slice s. it returns where x is, or
+// where it would go if it isn't there, and whether it was found
+func BinarySearch[T cmp.Ordered](s []T, x T) (int, bool) {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1) // no overflow, same as sort.Search
+		if s[mid] < x {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, lo < len(s) && s[lo] == x
+}
+
+// SortedInsert inserts x into the sorted slice s and returns the result,
+// like append does. x goes after any elements equal to it
+func SortedInsert[T cmp.Ordered](s []T, x T) []T {
+	i, _ := BinarySearch(s, x)
+	for i < len(s) && s[i] == x {
+		i++
+	}
+	var zero T
+	s = append(s, zero)
+	copy(s[i+1:], s[i:])
+	s[i] = x
+	return s
+}
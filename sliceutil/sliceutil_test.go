@@ -0,0 +1,452 @@
+package sliceutil
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	for _, tt := range []struct {
+		s           []int
+		x           int
+		first, last int
+	}{
+		{nil, 1, -1, -1},
+		{[]int{}, 1, -1, -1},
+		{[]int{1}, 1, 0, 0},
+		{[]int{1, 2, 1, 3}, 1, 0, 2},
+		{[]int{1, 2, 3}, 4, -1, -1},
+	} {
+		if got := Index(tt.s, tt.x); got != tt.first {
+			t.Errorf("Index(%v, %d) = %d, want %d", tt.s, tt.x, got, tt.first)
+		}
+		if got := LastIndex(tt.s, tt.x); got != tt.last {
+			t.Errorf("LastIndex(%v, %d) = %d, want %d", tt.s, tt.x, got, tt.last)
+		}
+		if got := IndexFunc(tt.s, func(v int) bool { return v == tt.x }); got != tt.first {
+			t.Errorf("IndexFunc(%v, == %d) = %d, want %d", tt.s, tt.x, got, tt.first)
+		}
+		if got := Contains(tt.s, tt.x); got != (tt.first >= 0) {
+			t.Errorf("Contains(%v, %d) = %v", tt.s, tt.x, got)
+		}
+	}
+}
+
+func even(n int) bool { return n%2 == 0 }
+
+func TestMapFilterReduce(t *testing.T) {
+	for _, tt := range []struct {
+		s        []int
+		mapped   []string
+		filtered []int
+		sum      int
+	}{
+		{nil, []string{}, nil, 0},
+		{[]int{}, []string{}, nil, 0},
+		{[]int{1, 2, 3, 4}, []string{"1", "2", "3", "4"}, []int{2, 4}, 10},
+		{[]int{1, 3}, []string{"1", "3"}, nil, 4},
+	} {
+		if got := Map(tt.s, strconv.Itoa); !reflect.DeepEqual(got, tt.mapped) {
+			t.Errorf("Map(%v) = %#v, want %#v", tt.s, got, tt.mapped)
+		}
+		if got := Filter(tt.s, even); !reflect.DeepEqual(got, tt.filtered) {
+			t.Errorf("Filter(%v) = %#v, want %#v", tt.s, got, tt.filtered)
+		}
+		if got := Reduce(tt.s, 0, func(acc, v int) int { return acc + v }); got != tt.sum {
+			t.Errorf("Reduce(%v) = %d, want %d", tt.s, got, tt.sum)
+		}
+	}
+}
+
+func TestChunk(t *testing.T) {
+	for _, tt := range []struct {
+		s    []int
+		n    int
+		want string
+	}{
+		{nil, 2, "[]"},
+		{[]int{}, 2, "[]"},
+		{[]int{1, 2, 3, 4, 5}, 2, "[[1 2] [3 4] [5]]"},
+		{[]int{1, 2, 3, 4}, 2, "[[1 2] [3 4]]"},
+		{[]int{1, 2}, 5, "[[1 2]]"},
+	} {
+		if got := fmt.Sprint(Chunk(tt.s, tt.n)); got != tt.want {
+			t.Errorf("Chunk(%v, %d) = %s, want %s", tt.s, tt.n, got, tt.want)
+		}
+	}
+
+	// appending to a chunk mustn't write over the next one
+	s := []int{1, 2, 3, 4}
+	c := Chunk(s, 2)
+	_ = append(c[0], 99)
+	if !slices.Equal(s, []int{1, 2, 3, 4}) {
+		t.Errorf("append to a chunk changed s to %v", s)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Chunk(s, 0) didn't panic")
+		}
+	}()
+	Chunk(s, 0)
+}
+
+func TestPartition(t *testing.T) {
+	for _, tt := range []struct {
+		s       []int
+		yes, no []int
+	}{
+		{nil, nil, nil},
+		{[]int{}, nil, nil},
+		{[]int{1, 2, 3, 4, 6}, []int{2, 4, 6}, []int{1, 3}},
+	} {
+		yes, no := Partition(tt.s, even)
+		if !reflect.DeepEqual(yes, tt.yes) || !reflect.DeepEqual(no, tt.no) {
+			t.Errorf("Partition(%v) = %v, %v, want %v, %v", tt.s, yes, no, tt.yes, tt.no)
+		}
+	}
+}
+
+func TestUniq(t *testing.T) {
+	for _, tt := range []struct {
+		s, want []string
+	}{
+		{nil, nil},
+		{[]string{}, nil},
+		{[]string{"b", "a", "b", "c", "a"}, []string{"b", "a", "c"}},
+	} {
+		if got := Uniq(tt.s); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Uniq(%v) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	got := GroupBy([]string{"go", "rust", "c", "zig", "js"}, func(s string) int { return len(s) })
+	want := map[int][]string{1: {"c"}, 2: {"go", "js"}, 3: {"zig"}, 4: {"rust"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupBy = %v, want %v", got, want)
+	}
+	if got := GroupBy(nil, strings.ToUpper); len(got) != 0 {
+		t.Errorf("GroupBy(nil) = %v", got)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	for _, tt := range []struct {
+		s        []float64
+		min, max float64
+		ok       bool
+	}{
+		{nil, 0, 0, false},
+		{[]float64{}, 0, 0, false},
+		{[]float64{3}, 3, 3, true},
+		{[]float64{3, -1, 7, 2}, -1, 7, true},
+	} {
+		lo, ok1 := Min(tt.s)
+		hi, ok2 := Max(tt.s)
+		if lo != tt.min || hi != tt.max || ok1 != tt.ok || ok2 != tt.ok {
+			t.Errorf("Min, Max(%v) = %g %v, %g %v", tt.s, lo, ok1, hi, ok2)
+		}
+	}
+}
+
+func TestBinarySearch(t *testing.T) {
+	for _, tt := range []struct {
+		s     []int
+		x     int
+		i     int
+		found bool
+	}{
+		{nil, 5, 0, false},
+		{[]int{}, 5, 0, false},
+		{[]int{1, 3, 5, 7}, 5, 2, true},
+		{[]int{1, 3, 5, 7}, 4, 2, false},
+		{[]int{1, 3, 5, 7}, 0, 0, false},
+		{[]int{1, 3, 5, 7}, 9, 4, false},
+		{[]int{2, 2, 2}, 2, 0, true},
+	} {
+		i, found := BinarySearch(tt.s, tt.x)
+		if i != tt.i || found != tt.found {
+			t.Errorf("BinarySearch(%v, %d) = %d, %v, want %d, %v", tt.s, tt.x, i, found, tt.i, tt.found)
+		}
+		// same answer as the standard library
+		if j, ok := slices.BinarySearch(tt.s, tt.x); i != j || found != ok {
+			t.Errorf("BinarySearch(%v, %d) = %d, %v but slices says %d, %v", tt.s, tt.x, i, found, j, ok)
+		}
+	}
+}
+
+func TestSortedInsert(t *testing.T) {
+	var s []int
+	for _, x := range []int{5, 1, 4, 1, 9, 0} {
+		s = SortedInsert(s, x)
+	}
+	if want := []int{0, 1, 1, 4, 5, 9}; !slices.Equal(s, want) {
+		t.Errorf("got %v, want %v", s, want)
+	}
+}
+
+// each benchmark pairs the generic function with the loop you'd write by
+// hand for []int, to see what the type parameter and the func value cost
+
+var (
+	ints = func() []int {
+		s := make([]int, 10000)
+		for i := range s {
+			s[i] = i * 3
+		}
+		return s
+	}()
+	sinkInt    int
+	sinkInts   []int
+	sinkChunks [][]int
+	sinkGroups map[int][]int
+	sinkFound  bool
+)
+
+func BenchmarkIndex(b *testing.B) {
+	x := ints[len(ints)-1]
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkInt = Index(ints, x)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			sinkInt = -1
+			for i, v := range ints {
+				if v == x {
+					sinkInt = i
+					break
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkMap(b *testing.B) {
+	double := func(n int) int { return n * 2 }
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkInts = Map(ints, double)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			out := make([]int, len(ints))
+			for i, v := range ints {
+				out[i] = v * 2
+			}
+			sinkInts = out
+		}
+	})
+}
+
+func BenchmarkFilter(b *testing.B) {
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkInts = Filter(ints, even)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			var out []int
+			for _, v := range ints {
+				if v%2 == 0 {
+					out = append(out, v)
+				}
+			}
+			sinkInts = out
+		}
+	})
+}
+
+func BenchmarkReduce(b *testing.B) {
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkInt = Reduce(ints, 0, func(acc, v int) int { return acc + v })
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			sum := 0
+			for _, v := range ints {
+				sum += v
+			}
+			sinkInt = sum
+		}
+	})
+}
+
+func BenchmarkMax(b *testing.B) {
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkInt, sinkFound = Max(ints)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			m := ints[0]
+			for _, v := range ints[1:] {
+				if v > m {
+					m = v
+				}
+			}
+			sinkInt = m
+		}
+	})
+}
+
+func BenchmarkBinarySearch(b *testing.B) {
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkInt, sinkFound = BinarySearch(ints, 2999)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			lo, hi := 0, len(ints)
+			for lo < hi {
+				mid := int(uint(lo+hi) >> 1)
+				if ints[mid] < 2999 {
+					lo = mid + 1
+				} else {
+					hi = mid
+				}
+			}
+			sinkInt = lo
+		}
+	})
+}
+
+func BenchmarkChunk(b *testing.B) {
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkChunks = Chunk(ints, 64)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			chunks := make([][]int, 0, (len(ints)+63)/64)
+			for i := 0; i < len(ints); i += 64 {
+				end := min(i+64, len(ints))
+				chunks = append(chunks, ints[i:end:end])
+			}
+			sinkChunks = chunks
+		}
+	})
+}
+
+func BenchmarkPartition(b *testing.B) {
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkInts, _ = Partition(ints, even)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			var yes, no []int
+			for _, v := range ints {
+				if v%2 == 0 {
+					yes = append(yes, v)
+				} else {
+					no = append(no, v)
+				}
+			}
+			sinkInts, _ = yes, no
+		}
+	})
+}
+
+// a value out of 100, so Uniq has plenty to throw away
+func mod100(n int) int { return n % 100 }
+
+func BenchmarkUniq(b *testing.B) {
+	repeats := Map(ints, mod100)
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkInts = Uniq(repeats)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			seen := make(map[int]bool, len(repeats))
+			var out []int
+			for _, v := range repeats {
+				if !seen[v] {
+					seen[v] = true
+					out = append(out, v)
+				}
+			}
+			sinkInts = out
+		}
+	})
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkGroups = GroupBy(ints, mod100)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			groups := make(map[int][]int)
+			for _, v := range ints {
+				groups[v%100] = append(groups[v%100], v)
+			}
+			sinkGroups = groups
+		}
+	})
+}
+
+func BenchmarkMin(b *testing.B) {
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkInt, sinkFound = Min(ints)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			m := ints[0]
+			for _, v := range ints[1:] {
+				if v < m {
+					m = v
+				}
+			}
+			sinkInt = m
+		}
+	})
+}
+
+// ints has no room on the end, so both copy the whole slice every time. that
+// is most of the cost, same as it would be for real
+func BenchmarkSortedInsert(b *testing.B) {
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			sinkInts = SortedInsert(ints, 2999)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			lo, hi := 0, len(ints)
+			for lo < hi {
+				mid := int(uint(lo+hi) >> 1)
+				if ints[mid] <= 2999 {
+					lo = mid + 1
+				} else {
+					hi = mid
+				}
+			}
+			s := append(ints, 0)
+			copy(s[lo+1:], s[lo:])
+			s[lo] = 2999
+			sinkInts = s
+		}
+	})
+}
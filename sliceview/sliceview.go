@@ -0,0 +1,195 @@
+// Package sliceview draws what the slices lessons only describe in comments.
+// a slice is a little header, a pointer to an element of some array plus a
+// len and a cap. two slices that point into the same array see each other's
+// writes, and append only copies to a new array when the cap runs out.
+// unsafe lets us read the pointer so we can show which slices share what:
+//
+//	array A, 4 x string
+//	          0      1     2        3
+//	  names | John | XXX | George | Ringo |  off=0 len=4 cap=4
+//	  a     | John | XXX | .      | .     |  off=0 len=2 cap=4
+//	  b            | XXX | George | .     |  off=1 len=2 cap=3
+//
+// a . is a cell the slice could grow into with append or s[:cap(s)] but
+// doesn't currently include
+package sliceview
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// View is a snapshot of one slice header and everything it can reach
+type View struct {
+	Name     string
+	Data     uintptr // address of s[0], or of where it would be
+	Len, Cap int
+	Size     uintptr // bytes per element
+	Type     string  // element type, eg. int
+	Nil      bool
+	Values   []string // s[:cap(s)] formatted with %v
+}
+
+// Of takes a snapshot of s. later writes through s don't change the view
+func Of[T any](name string, s []T) View {
+	v := View{
+		Name: name,
+		Data: uintptr(unsafe.Pointer(unsafe.SliceData(s))),
+		Len:  len(s),
+		Cap:  cap(s),
+		Size: unsafe.Sizeof(*new(T)),
+		Type: reflect.TypeFor[T]().String(),
+		Nil:  s == nil,
+	}
+	for _, x := range s[:cap(s)] {
+		v.Values = append(v.Values, fmt.Sprint(x))
+	}
+	return v
+}
+
+// end is one past the last byte the view can reach
+func (v View) end() uintptr {
+	return v.Data + uintptr(v.Cap)*v.Size
+}
+
+// SameArray reports whether a and b can reach any of the same elements, so
+// writing through one might show up in the other
+func SameArray[T any](a, b []T) bool {
+	va, vb := Of("", a), Of("", b)
+	return va.Cap > 0 && vb.Cap > 0 && va.Data < vb.end() && vb.Data < va.end()
+}
+
+// Reallocated reports whether append moved the slice to a new array, ie.
+// after = append(before, ...) had to copy because before was out of cap.
+// when it didn't, before and after still share and writes to one show in
+// the other
+func Reallocated[T any](before, after []T) bool {
+	return unsafe.SliceData(before) != unsafe.SliceData(after)
+}
+
+// array is a group of views that overlap. we only know the part of the
+// real array the views can reach, it might start earlier
+type array struct {
+	start, end uintptr
+	size       uintptr
+	typ        string
+	views      []View
+}
+
+// Fprint draws the views grouped by the array they point into. the arrays
+// are named A, B, C...
+func Fprint(w io.Writer, views ...View) error {
+	var arrays []*array
+	var empty []View
+	sorted := slices.Clone(views)
+	slices.SortStableFunc(sorted, func(a, b View) int { return cmp.Compare(a.Data, b.Data) })
+	for _, v := range sorted {
+		if v.Cap == 0 {
+			empty = append(empty, v)
+			continue
+		}
+		if n := len(arrays); n > 0 {
+			a := arrays[n-1]
+			if a.typ == v.Type && v.Data < a.end && (v.Data-a.start)%a.size == 0 {
+				a.end = max(a.end, v.end())
+				a.views = append(a.views, v)
+				continue
+			}
+		}
+		arrays = append(arrays, &array{start: v.Data, end: v.end(), size: v.Size, typ: v.Type, views: []View{v}})
+	}
+
+	// addresses change from run to run, so put the arrays and the slices in
+	// each one in the order they were passed in instead
+	order := map[string]int{}
+	for i, v := range views {
+		order[v.Name] = i
+	}
+	for _, a := range arrays {
+		slices.SortStableFunc(a.views, func(x, y View) int { return cmp.Compare(order[x.Name], order[y.Name]) })
+	}
+	slices.SortStableFunc(arrays, func(x, y *array) int {
+		return cmp.Compare(order[x.views[0].Name], order[y.views[0].Name])
+	})
+	width := 0
+	for _, v := range views {
+		width = max(width, utf8.RuneCountInString(v.Name))
+	}
+
+	var b strings.Builder
+	for i, a := range arrays {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		a.draw(&b, string(rune('A'+i%26)), width)
+	}
+	if len(empty) > 0 && len(arrays) > 0 {
+		b.WriteByte('\n')
+	}
+	for _, v := range empty {
+		what := "empty, no array"
+		if v.Nil {
+			what = "nil, no array"
+		}
+		fmt.Fprintf(&b, "  %-*s  %s  len=0 cap=0\n", width, v.Name, what)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (a *array) draw(b *strings.Builder, label string, width int) {
+	n := int((a.end - a.start) / a.size)
+	fmt.Fprintf(b, "array %s, %d x %s\n", label, n, a.typ)
+
+	// the cells and how wide each column has to be
+	cells := make([][]string, len(a.views))
+	cols := make([]int, n)
+	for i := range cols {
+		cols[i] = len(fmt.Sprint(i))
+	}
+	for i, v := range a.views {
+		off := int((v.Data - a.start) / a.size)
+		cells[i] = make([]string, off+v.Cap)
+		for j, val := range v.Values {
+			if j >= v.Len {
+				val = "."
+			}
+			cells[i][off+j] = val
+			// fmt pads by runes, not bytes
+			cols[off+j] = max(cols[off+j], utf8.RuneCountInString(val))
+		}
+	}
+
+	var row strings.Builder
+	fmt.Fprintf(&row, "  %-*s ", width, "")
+	for i, c := range cols {
+		fmt.Fprintf(&row, "  %-*d", c+1, i)
+	}
+	b.WriteString(strings.TrimRight(row.String(), " "))
+	b.WriteByte('\n')
+
+	full := 2 + width + 1
+	for _, c := range cols {
+		full += c + 3
+	}
+	for i, v := range a.views {
+		row.Reset()
+		fmt.Fprintf(&row, "  %-*s ", width, v.Name)
+		off := int((v.Data - a.start) / a.size)
+		for j, c := range cols[:off+v.Cap] {
+			if j < off {
+				row.WriteString(strings.Repeat(" ", c+3))
+				continue
+			}
+			fmt.Fprintf(&row, "| %-*s ", c, cells[i][j])
+		}
+		row.WriteByte('|')
+		fmt.Fprintf(b, "%-*s  off=%d len=%d cap=%d\n", full+1, row.String(), off, v.Len, v.Cap)
+	}
+}
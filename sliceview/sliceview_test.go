@@ -0,0 +1,155 @@
+package sliceview
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// the slices from the package doc
+func beatles() (names, a, b []string) {
+	names = []string{"John", "Paul", "George", "Ringo"}
+	a = names[0:2]
+	b = names[1:3]
+	b[0] = "XXX"
+	return names, a, b
+}
+
+func TestOf(t *testing.T) {
+	names, _, b := beatles()
+	v := Of("b", b)
+	if v.Name != "b" || v.Len != 2 || v.Cap != 3 || v.Type != "string" || v.Nil {
+		t.Errorf("Of(b) = %+v", v)
+	}
+	if want := []string{"XXX", "George", "Ringo"}; !slices.Equal(v.Values, want) {
+		t.Errorf("Values = %q, want %q, the whole of b[:cap(b)]", v.Values, want)
+	}
+	if v.Size != Of("names", names).Size {
+		t.Errorf("Size = %d, not the same as names", v.Size)
+	}
+
+	// it's a snapshot
+	b[1] = "Paul"
+	if v.Values[1] != "George" {
+		t.Errorf("Values changed to %q after a write", v.Values)
+	}
+
+	var nilSlice []int
+	if v := Of("nil", nilSlice); !v.Nil || v.Len != 0 || v.Cap != 0 || v.Values != nil {
+		t.Errorf("Of(nil) = %+v", v)
+	}
+	if v := Of("empty", []int{}); v.Nil || v.Cap != 0 || v.Values != nil {
+		t.Errorf("Of(empty) = %+v", v)
+	}
+	if v := Of("wide", []int64{1}); v.Size != 8 || v.Type != "int64" {
+		t.Errorf("Of(wide) = %+v", v)
+	}
+}
+
+func TestSameArray(t *testing.T) {
+	names, a, b := beatles()
+	var nilSlice []string
+	for _, tt := range []struct {
+		name string
+		x, y []string
+		want bool
+	}{
+		{"names a", names, a, true},
+		{"a b", a, b, true},
+		{"b a", b, a, true},
+		// a's len stops short of b's end but its cap doesn't
+		{"a[:1] b", a[:1], b, true},
+		{"full slice expression", a[:1:1], b, false},
+		{"neighbours", names[:2:2], names[2:], false},
+		{"clone", names, slices.Clone(names), false},
+		{"itself", b, b, true},
+		{"nil", names, nilSlice, false},
+		{"nil nil", nilSlice, nilSlice, false},
+		{"empty", names, []string{}, false},
+		// no cap left, so nothing to share even though it points at the end
+		{"past the end", names, names[4:], false},
+	} {
+		if got := SameArray(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: SameArray = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReallocated(t *testing.T) {
+	s := make([]int, 1, 2)
+	grown := append(s, 1)
+	if Reallocated(s, grown) {
+		t.Error("append within cap reallocated")
+	}
+	moved := append(grown, 2)
+	if !Reallocated(grown, moved) {
+		t.Error("append past cap didn't reallocate")
+	}
+	var nilSlice []int
+	if !Reallocated(nilSlice, append(nilSlice, 1)) {
+		t.Error("append to nil didn't reallocate")
+	}
+}
+
+func TestFprint(t *testing.T) {
+	names, a, b := beatles()
+	wide := []int{1000, 2, 30000}
+	var nilSlice []int
+	for _, tt := range []struct {
+		name  string
+		views []View
+		want  string
+	}{
+		{"package doc", []View{Of("names", names), Of("a", a), Of("b", b)}, `
+array A, 4 x string
+          0      1     2        3
+  names | John | XXX | George | Ringo |  off=0 len=4 cap=4
+  a     | John | XXX | .      | .     |  off=0 len=2 cap=4
+  b            | XXX | George | .     |  off=1 len=2 cap=3
+`},
+		// passed in a different order, the arrays and rows follow it. no
+		// view reaches Ringo, so the last column is only as wide as a .
+		{"two arrays", []View{Of("b", b), Of("clone", slices.Clone(a)), Of("a", a)}, `
+array A, 4 x string
+          0      1     2        3
+  b            | XXX | George | . |  off=1 len=2 cap=3
+  a     | John | XXX | .      | . |  off=0 len=2 cap=4
+
+array B, 2 x string
+          0      1
+  clone | John | XXX |  off=0 len=2 cap=2
+`},
+		{"wide values", []View{Of("wide", wide), Of("tail", wide[2:]), Of("other", []string{"héllo", "x"})}, `
+array A, 3 x int
+          0      1   2
+  wide  | 1000 | 2 | 30000 |  off=0 len=3 cap=3
+  tail             | 30000 |  off=2 len=1 cap=1
+
+array B, 2 x string
+          0       1
+  other | héllo | x |  off=0 len=2 cap=2
+`},
+		{"nil and empty", []View{Of("s", wide[:1]), Of("nil", nilSlice), Of("empty", []int{})}, `
+array A, 3 x int
+          0      1   2
+  s     | 1000 | . | . |  off=0 len=1 cap=3
+
+  nil    nil, no array  len=0 cap=0
+  empty  empty, no array  len=0 cap=0
+`},
+		{"only nil", []View{Of("nil", nilSlice)}, `
+  nil  nil, no array  len=0 cap=0
+`},
+		{"nothing", nil, "\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := Fprint(&b, tt.views...); err != nil {
+				t.Fatal(err)
+			}
+			if want := tt.want[1:]; b.String() != want {
+				t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+			}
+		})
+	}
+}
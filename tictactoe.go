@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/DaveM7788/tourOfGo/tictactoe"
+)
+
+// tictactoeCmd plays the game from the sliceTacToe lesson for real
+func tictactoeCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("tictactoe", flag.ContinueOnError)
+	size := fs.Int("size", 3, "the board is size by size")
+	k := fs.Int("k", 0, "how many in a row wins. 0 means size")
+	ai := fs.String("ai", "o", "who the computer plays: x, o, both or none")
+	depth := fs.Int("depth", 0, "how far ahead the computer looks. 0 picks for you")
+	load := fs.String("load", "", "carry on from a replay file")
+	save := fs.String("save", "", "write the game to a replay file when it ends")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	computer := map[tictactoe.Mark]bool{}
+	switch *ai {
+	case "x":
+		computer[tictactoe.X] = true
+	case "o":
+		computer[tictactoe.O] = true
+	case "both":
+		computer[tictactoe.X], computer[tictactoe.O] = true, true
+	case "none":
+	default:
+		return fmt.Errorf("--ai wants x, o, both or none, not %q", *ai)
+	}
+
+	var b *tictactoe.Board
+	var err error
+	if *load != "" {
+		f, err := os.Open(*load)
+		if err != nil {
+			return err
+		}
+		b, err = tictactoe.ReadReplay(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	} else {
+		if *k == 0 {
+			*k = *size
+		}
+		if b, err = tictactoe.New(*size, *k); err != nil {
+			return err
+		}
+	}
+
+	t := tictactoe.Terminal{In: os.Stdin, Out: w, AI: tictactoe.AI{MaxDepth: *depth}, Computer: computer}
+	if err := t.Play(b); err != nil {
+		return err
+	}
+
+	if *save != "" {
+		f, err := os.Create(*save)
+		if err != nil {
+			return err
+		}
+		if err := b.WriteReplay(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package tictactoe
+
+import "math"
+
+// AI picks moves with minimax and alpha-beta pruning. on a 3x3 board it
+// searches every possible game, so it never loses. bigger boards have far too
+// many games for that, so it looks MaxDepth moves ahead and guesses from there
+type AI struct {
+	// MaxDepth limits how many moves ahead to look. 0 means search to the end
+	// when there are at most 10 empty cells and 4 moves otherwise
+	MaxDepth int
+}
+
+const winScore = 1_000_000
+
+// BestMove returns the move the AI would make. ok is false if the game is over
+func (ai AI) BestMove(b *Board) (m Move, ok bool) {
+	if b.State() != InProgress {
+		return Move{}, false
+	}
+	depth := ai.MaxDepth
+	if depth <= 0 {
+		depth = 4
+		if empties := len(b.cells) - len(b.moves); empties <= 10 {
+			depth = empties
+		}
+	}
+
+	// not math.MinInt. negating that overflows back to itself
+	alpha, beta := -2*winScore, 2*winScore
+	best := Move{-1, -1}
+	for _, mv := range b.ordered() {
+		b.play(mv)
+		score := -b.negamax(depth-1, -beta, -alpha)
+		b.undo()
+		if best.Row < 0 || score > alpha {
+			alpha, best = score, mv
+		}
+	}
+	return best, true
+}
+
+// negamax is minimax written from the point of view of whoever is to move,
+// so the score of a position for me is minus its score for the other player.
+// alpha is the best i'm already guaranteed, beta the best the other player is.
+// once a move scores beta or more the other player would never allow it, so
+// there's no point looking at the rest
+func (b *Board) negamax(depth, alpha, beta int) int {
+	if b.winner != Empty {
+		// the last move won, so whoever is to move now has lost. losing later
+		// is better than losing sooner, which makes the AI drag out lost games
+		// and win won ones as quickly as it can
+		return -(winScore - len(b.moves))
+	}
+	if len(b.moves) == len(b.cells) {
+		return 0
+	}
+	if depth == 0 {
+		return b.heuristic()
+	}
+	for _, mv := range b.ordered() {
+		b.play(mv)
+		score := -b.negamax(depth-1, -beta, -alpha)
+		b.undo()
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return alpha
+}
+
+// ordered returns the empty cells middle first. good moves early means more
+// pruning, and the middle is usually where the good moves are
+func (b *Board) ordered() []Move {
+	ms := b.Empties()
+	mid := float64(b.n-1) / 2
+	dist := func(m Move) float64 { return math.Abs(float64(m.Row)-mid) + math.Abs(float64(m.Col)-mid) }
+	// insertion sort keeps equal cells in row order so the AI is predictable
+	for i := 1; i < len(ms); i++ {
+		for j := i; j > 0 && dist(ms[j]) < dist(ms[j-1]); j-- {
+			ms[j], ms[j-1] = ms[j-1], ms[j]
+		}
+	}
+	return ms
+}
+
+// heuristic scores an unfinished position for whoever is to move. every
+// stretch of K cells that only one player has marks in could still become
+// their line, and the more marks they have in it the better
+func (b *Board) heuristic() int {
+	me := b.Turn()
+	score := 0
+	for r := 0; r < b.n; r++ {
+		for c := 0; c < b.n; c++ {
+			for _, d := range directions {
+				er, ec := r+d[0]*(b.k-1), c+d[1]*(b.k-1)
+				if er < 0 || er >= b.n || ec < 0 || ec >= b.n {
+					continue
+				}
+				var mine, theirs int
+				for i := 0; i < b.k; i++ {
+					switch b.cells[(r+d[0]*i)*b.n+c+d[1]*i] {
+					case me:
+						mine++
+					case me.Other():
+						theirs++
+					}
+				}
+				switch {
+				case theirs == 0:
+					score += mine * mine
+				case mine == 0:
+					score -= theirs * theirs
+				}
+			}
+		}
+	}
+	return score
+}
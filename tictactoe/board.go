@@ -0,0 +1,193 @@
+// Package tictactoe turns the slice of slices board from sliceTacToe into a
+// game you can actually play. boards can be any size N and need K in a row to
+// win, so 3x3 with 3 in a row is the usual game and 15x15 with 5 is gomoku
+package tictactoe
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Mark is what's in a cell
+type Mark byte
+
+const (
+	Empty Mark = iota
+	X
+	O
+)
+
+func (m Mark) String() string {
+	switch m {
+	case X:
+		return "X"
+	case O:
+		return "O"
+	}
+	return "_"
+}
+
+// Other returns the other player
+func (m Mark) Other() Mark {
+	if m == X {
+		return O
+	}
+	return X
+}
+
+// Move is a cell, 0 based like slice indexes
+type Move struct {
+	Row, Col int
+}
+
+// State is how the game is going
+type State int
+
+const (
+	InProgress State = iota
+	Won
+	Draw
+)
+
+var (
+	ErrOutOfRange = errors.New("tictactoe: that cell isn't on the board")
+	ErrTaken      = errors.New("tictactoe: that cell is taken")
+	ErrGameOver   = errors.New("tictactoe: the game is over")
+)
+
+// Board is an N by N game needing K in a row. X always goes first
+type Board struct {
+	n, k   int
+	cells  []Mark // row by row, cells[row*n+col]. one slice instead of a slice of slices
+	moves  []Move
+	winner Mark
+}
+
+// New returns an empty n by n board needing k in a row to win
+func New(n, k int) (*Board, error) {
+	if n < 1 || k < 1 || k > n {
+		return nil, fmt.Errorf("tictactoe: can't play %d in a row on a %dx%d board", k, n, n)
+	}
+	return &Board{n: n, k: k, cells: make([]Mark, n*n)}, nil
+}
+
+// Size returns N and K
+func (b *Board) Size() (n, k int) { return b.n, b.k }
+
+// At returns what's in a cell
+func (b *Board) At(m Move) Mark { return b.cells[m.Row*b.n+m.Col] }
+
+// Turn returns whose go it is
+func (b *Board) Turn() Mark {
+	if len(b.moves)%2 == 0 {
+		return X
+	}
+	return O
+}
+
+// Moves returns the moves so far in order
+func (b *Board) Moves() []Move { return append([]Move(nil), b.moves...) }
+
+// State reports whether the game is won, drawn or still going
+func (b *Board) State() State {
+	switch {
+	case b.winner != Empty:
+		return Won
+	case len(b.moves) == len(b.cells):
+		return Draw
+	}
+	return InProgress
+}
+
+// Winner returns who won, or Empty if nobody has (yet)
+func (b *Board) Winner() Mark { return b.winner }
+
+// Play puts the current player's mark on m
+func (b *Board) Play(m Move) error {
+	switch {
+	case b.State() != InProgress:
+		return ErrGameOver
+	case m.Row < 0 || m.Row >= b.n || m.Col < 0 || m.Col >= b.n:
+		return ErrOutOfRange
+	case b.At(m) != Empty:
+		return ErrTaken
+	}
+	b.play(m)
+	return nil
+}
+
+// Undo takes back the last move. it reports false if there wasn't one
+func (b *Board) Undo() bool {
+	if len(b.moves) == 0 {
+		return false
+	}
+	b.undo()
+	return true
+}
+
+// play and undo skip the checks. the AI calls them millions of times
+func (b *Board) play(m Move) {
+	p := b.Turn()
+	b.cells[m.Row*b.n+m.Col] = p
+	b.moves = append(b.moves, m)
+	if b.lineThrough(m) >= b.k {
+		b.winner = p
+	}
+}
+
+func (b *Board) undo() {
+	m := b.moves[len(b.moves)-1]
+	b.moves = b.moves[:len(b.moves)-1]
+	b.cells[m.Row*b.n+m.Col] = Empty
+	b.winner = Empty // there can't have been a winner before the last move
+}
+
+// the four ways to make a line: across, down and both diagonals
+var directions = [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// lineThrough returns the longest run of the same mark through m. only the
+// last move can have made a new line, so that's all that needs checking
+func (b *Board) lineThrough(m Move) int {
+	p := b.At(m)
+	best := 0
+	for _, d := range directions {
+		run := 1 + b.count(m, d[0], d[1], p) + b.count(m, -d[0], -d[1], p)
+		best = max(best, run)
+	}
+	return best
+}
+
+// count returns how many p's there are in a row starting next to m going dr, dc
+func (b *Board) count(m Move, dr, dc int, p Mark) int {
+	n := 0
+	for r, c := m.Row+dr, m.Col+dc; r >= 0 && r < b.n && c >= 0 && c < b.n && b.cells[r*b.n+c] == p; r, c = r+dr, c+dc {
+		n++
+	}
+	return n
+}
+
+// Empties returns the free cells row by row
+func (b *Board) Empties() []Move {
+	var ms []Move
+	for i, c := range b.cells {
+		if c == Empty {
+			ms = append(ms, Move{i / b.n, i % b.n})
+		}
+	}
+	return ms
+}
+
+// String prints the board the same way sliceTacToe does
+func (b *Board) String() string {
+	var sb strings.Builder
+	for r := 0; r < b.n; r++ {
+		row := make([]string, b.n)
+		for c := range row {
+			row[c] = b.At(Move{r, c}).String()
+		}
+		sb.WriteString(strings.Join(row, " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
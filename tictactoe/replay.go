@@ -0,0 +1,73 @@
+package tictactoe
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// a replay is a small text file. a header with the board size then one move
+// per line, 1 based so it matches what you type when playing:
+//
+//	tictactoe 3 3
+//	X 1 1
+//	O 3 3
+//	X 2 3
+//
+// the marks are only there for people reading it. they are checked though
+
+// WriteReplay saves the game so far
+func (b *Board) WriteReplay(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "tictactoe %d %d\n", b.n, b.k)
+	p := X
+	for _, m := range b.moves {
+		fmt.Fprintf(bw, "%s %d %d\n", p, m.Row+1, m.Col+1)
+		p = p.Other()
+	}
+	return bw.Flush()
+}
+
+// ReadReplay loads a game saved by WriteReplay, checking every move is legal
+func ReadReplay(r io.Reader) (*Board, error) {
+	sc := bufio.NewScanner(r)
+	var b *Board
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if b == nil {
+			var n, k int
+			if _, err := fmt.Sscanf(text, "tictactoe %d %d", &n, &k); err != nil {
+				return nil, fmt.Errorf("tictactoe: replay line %d: want a tictactoe N K header", line)
+			}
+			var err error
+			if b, err = New(n, k); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		var mark string
+		var m Move
+		if _, err := fmt.Sscanf(text, "%s %d %d", &mark, &m.Row, &m.Col); err != nil {
+			return nil, fmt.Errorf("tictactoe: replay line %d: want a move like X 1 1", line)
+		}
+		if mark != b.Turn().String() {
+			return nil, fmt.Errorf("tictactoe: replay line %d: it's %s's turn, not %s", line, b.Turn(), mark)
+		}
+		m.Row--
+		m.Col--
+		if err := b.Play(m); err != nil {
+			return nil, fmt.Errorf("tictactoe: replay line %d: %w", line, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, fmt.Errorf("tictactoe: empty replay")
+	}
+	return b, nil
+}
@@ -0,0 +1,89 @@
+package tictactoe
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Terminal plays a game over a reader and writer, normally stdin and stdout
+type Terminal struct {
+	In  io.Reader
+	Out io.Writer
+	AI  AI
+
+	// Computer says which players the AI moves for. nobody means two people
+	// take turns at the keyboard, both means sit back and watch
+	Computer map[Mark]bool
+}
+
+// Play runs the game on b until it's over, the player types quit or In runs
+// out. people enter moves as "row col" counting from 1, or undo
+func (t *Terminal) Play(b *Board) error {
+	in := bufio.NewScanner(t.In)
+	for b.State() == InProgress {
+		fmt.Fprint(t.Out, "\n", b.Grid())
+		p := b.Turn()
+		if t.Computer[p] {
+			m, _ := t.AI.BestMove(b)
+			b.play(m)
+			fmt.Fprintf(t.Out, "%s plays %d %d\n", p, m.Row+1, m.Col+1)
+			continue
+		}
+
+		fmt.Fprintf(t.Out, "%s to play (row col, undo or quit): ", p)
+		if !in.Scan() {
+			fmt.Fprintln(t.Out)
+			return in.Err()
+		}
+		cmd := strings.TrimSpace(in.Text())
+		switch cmd {
+		case "quit", "q":
+			return nil
+		case "undo", "u":
+			// take back the computer's reply too, or it just plays it again
+			b.Undo()
+			for len(b.moves) > 0 && t.Computer[b.Turn()] {
+				b.Undo()
+			}
+			continue
+		}
+		var m Move
+		if _, err := fmt.Sscanf(cmd, "%d %d", &m.Row, &m.Col); err != nil {
+			fmt.Fprintln(t.Out, "type a row and a column, eg. 2 2 for the middle")
+			continue
+		}
+		m.Row--
+		m.Col--
+		if err := b.Play(m); err != nil {
+			fmt.Fprintln(t.Out, strings.TrimPrefix(err.Error(), "tictactoe: "))
+			if !errors.Is(err, ErrGameOver) {
+				continue
+			}
+		}
+	}
+
+	fmt.Fprint(t.Out, "\n", b.Grid())
+	if b.State() == Won {
+		fmt.Fprintf(t.Out, "%s wins!\n", b.Winner())
+	} else {
+		fmt.Fprintln(t.Out, "it's a draw")
+	}
+	return nil
+}
+
+// Grid is String with row and column numbers round the edge
+func (b *Board) Grid() string {
+	var sb strings.Builder
+	sb.WriteString("  ")
+	for c := 1; c <= b.n; c++ {
+		fmt.Fprintf(&sb, " %d", c%10)
+	}
+	sb.WriteByte('\n')
+	for r, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		fmt.Fprintf(&sb, "%2d %s\n", r+1, line)
+	}
+	return sb.String()
+}
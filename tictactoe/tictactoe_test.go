@@ -0,0 +1,131 @@
+package tictactoe
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// neverLoses plays every possible game where ai is one side and the other
+// side tries every legal move, and fails t if any of them ends with ai losing
+func neverLoses(t *testing.T, b *Board, ai Mark) (games int) {
+	t.Helper()
+	if b.State() != InProgress {
+		if b.Winner() == ai.Other() {
+			t.Errorf("AI lost as %s:\n%s", ai, b)
+		}
+		return 1
+	}
+	if b.Turn() == ai {
+		m, ok := AI{}.BestMove(b)
+		if !ok {
+			t.Fatalf("no move in a game in progress:\n%s", b)
+		}
+		if err := b.Play(m); err != nil {
+			t.Fatalf("AI played %v: %v", m, err)
+		}
+		games = neverLoses(t, b, ai)
+		b.Undo()
+		return games
+	}
+	for _, m := range b.Empties() {
+		b.Play(m)
+		games += neverLoses(t, b, ai)
+		b.Undo()
+	}
+	return games
+}
+
+func TestAINeverLoses(t *testing.T) {
+	// the other side moves first, from every square
+	b, _ := New(3, 3)
+	if games := neverLoses(t, b, O); games == 0 {
+		t.Error("no games played")
+	}
+	// and the AI moves first
+	b, _ = New(3, 3)
+	neverLoses(t, b, X)
+}
+
+func TestAITakesWin(t *testing.T) {
+	b, _ := New(3, 3)
+	// X X .
+	// O O .
+	for _, m := range []Move{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		b.Play(m)
+	}
+	if m, _ := (AI{}).BestMove(b); m != (Move{0, 2}) {
+		t.Errorf("X played %v, not the win at 0 2", m)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	b, _ := New(3, 3)
+	if err := b.Play(Move{3, 0}); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("off the board: %v", err)
+	}
+	b.Play(Move{1, 1})
+	if err := b.Play(Move{1, 1}); !errors.Is(err, ErrTaken) {
+		t.Errorf("same cell twice: %v", err)
+	}
+	for _, m := range []Move{{0, 0}, {2, 2}, {0, 1}, {0, 2}, {1, 0}, {2, 0}} {
+		if err := b.Play(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// X on 1 1, 2 2, 0 2 and 2 0 is the anti-diagonal
+	if b.State() != Won || b.Winner() != X {
+		t.Errorf("state %v winner %v, want X to have won", b.State(), b.Winner())
+	}
+	if err := b.Play(Move{2, 1}); !errors.Is(err, ErrGameOver) {
+		t.Errorf("playing after a win: %v", err)
+	}
+	if _, err := New(3, 4); err == nil {
+		t.Error("4 in a row on 3x3 was allowed")
+	}
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	b, _ := New(4, 3)
+	for _, m := range []Move{{0, 0}, {3, 3}, {1, 2}, {2, 1}} {
+		b.Play(m)
+	}
+	var buf bytes.Buffer
+	if err := b.WriteReplay(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadReplay(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != b.String() {
+		t.Errorf("read back\n%s\nwant\n%s", got, b)
+	}
+}
+
+func TestReadReplayErrors(t *testing.T) {
+	for _, tt := range []struct {
+		in, msg string
+		is      error
+	}{
+		{"", "tictactoe: empty replay", nil},
+		{"hello", "tictactoe: replay line 1: want a tictactoe N K header", nil},
+		{"tictactoe 3 3\nX 1", "tictactoe: replay line 2: want a move like X 1 1", nil},
+		{"tictactoe 3 3\nO 1 1", "tictactoe: replay line 2: it's X's turn, not O", nil},
+		{"tictactoe 3 3\nX 1 1\nO 1 1", "tictactoe: replay line 3: ", ErrTaken},
+		{"tictactoe 3 3\n# a comment\nX 9 1", "tictactoe: replay line 3: ", ErrOutOfRange},
+	} {
+		_, err := ReadReplay(strings.NewReader(tt.in))
+		if err == nil {
+			t.Errorf("%q was accepted", tt.in)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), tt.msg) {
+			t.Errorf("%q: got %q, want %q", tt.in, err, tt.msg)
+		}
+		if tt.is != nil && !errors.Is(err, tt.is) {
+			t.Errorf("%q: %v isn't %v", tt.in, err, tt.is)
+		}
+	}
+}
@@ -12,11 +12,15 @@ Programs start running in package main
 //import "fmt" for a 1 liner. these are package imports
 import (
 	"fmt"
+	"io"
 	"math"
 	"math/cmplx"
-	"math/rand"
+	"os"
 	"runtime"
 	"time"
+
+	"github.com/DaveM7788/tourOfGo/lesson"
+	"github.com/DaveM7788/tourOfGo/whatif"
 )
 
 // these bools default to false. scoping works as expected
@@ -27,40 +31,58 @@ var c, python, java bool
 // const cannot use :=
 const Pi = 3.14
 
-// run the code as $ go run tour.go
-// or do $ go build tour.go && ./tour
+// init runs before main. every package can have one (or several)
+func init() {
+	for _, l := range []lesson.Lesson{
+		{Name: "helloWorld", Description: "printing, calling functions and declaring variables", RunEnv: helloWorld},
+		{Name: "basicTypes", Description: "bool, uint64 and complex128", Run: basicTypes},
+		{Name: "typeConversions", Description: "T(v) conversions and type inference", Run: typeConversions},
+		{Name: "numericConstants", Description: "untyped high precision constants", Run: numericConstants},
+		{Name: "loopingThereCanOnlyBeOne", Description: "for is go's only loop", Run: loopingThereCanOnlyBeOne},
+		{Name: "ifStmt", Description: "if with a short statement", Run: ifStmt},
+		{Name: "switchStmt", Description: "switch without fallthrough", RunEnv: switchStmt},
+		{Name: "deferStmt", Description: "deferred calls run LIFO", Run: deferStmt},
+	} {
+		l.Topic = "basics"
+		lesson.Register(l)
+	}
+}
+
+// run the code as $ go run . run helloWorld
+// or do $ go build -o tour && ./tour list
 func main() {
-	fmt.Println("hello world")
-	fmt.Println("chinese or japanese characters. hello 世界")
+	// tour whatif runs broken lessons in a copy of this program. in that copy
+	// this runs the lesson and exits
+	whatif.MaybeChild()
+	os.Exit(tour(os.Args[1:]))
+}
+
+func helloWorld(env *lesson.Env) {
+	w := env.Out
+	fmt.Fprintln(w, "hello world")
+	fmt.Fprintln(w, "chinese or japanese characters. hello 世界")
 	// those characters are actually chinese for "world". who would've known
-	fmt.Println("the time is ", time.Now())
-	fmt.Println("using a random number ", rand.Intn(10))
+	// the clock and random numbers come from env so tour verify can fake them
+	fmt.Fprintln(w, "the time is ", env.Clock.Now())
+	fmt.Fprintln(w, "using a random number ", env.Rand.Intn(10))
 
 	// all exported names begin with a capital letter. ie. can't do println
-	fmt.Println("calling a function", add(5, 7))
+	fmt.Fprintln(w, "calling a function", add(5, 7))
 
 	swapa, swapb := swap("hello", "world")
-	fmt.Println("swap function ", swapa, swapb)
+	fmt.Fprintln(w, "swap function ", swapa, swapb)
 
 	var idem int // defaults to 0
-	fmt.Println(idem, c, python, java)
+	fmt.Fprintln(w, idem, c, python, java)
 
 	// types can be omitted with initializers
 	var c2, python2, java2 = true, false, "no!"
 	// you can of course decalre variables 1 at a time too
-	fmt.Println(c2, python2, java2)
+	fmt.Fprintln(w, c2, python2, java2)
 
 	// := will infer the type of a var. must use inside a function
 	kinfer := 3
-	fmt.Println(kinfer)
-
-	basicTypes()
-	typeConversions()
-	numericConstants()
-	loopingThereCanOnlyBeOne()
-	ifStmt()
-	switchStmt()
-	deferStmt()
+	fmt.Fprintln(w, kinfer)
 }
 
 // could also have params as     x, y int
@@ -81,35 +103,35 @@ func split(sum int) (x, y int) {
 	return
 }
 
-func basicTypes() {
+func basicTypes(w io.Writer) {
 	var (
 		ToBe   bool       = false
 		MaxInt uint64     = 1<<64 - 1
 		z      complex128 = cmplx.Sqrt(-5 + 12i)
 	)
-	fmt.Printf("Type: %T Value: %v\n", ToBe, ToBe)
-	fmt.Printf("Type: %T Value: %v\n", MaxInt, MaxInt)
-	fmt.Printf("Type: %T Value: %v\n", z, z)
+	fmt.Fprintf(w, "Type: %T Value: %v\n", ToBe, ToBe)
+	fmt.Fprintf(w, "Type: %T Value: %v\n", MaxInt, MaxInt)
+	fmt.Fprintf(w, "Type: %T Value: %v\n", z, z)
 
 	// there's also strings, runes, bytes, and normal ints
 }
 
-func typeConversions() {
+func typeConversions(w io.Writer) {
 	// The expression T(v) converts the value v to the type T
 	i := 42
 	f := float64(i)
 	u := uint(f)
 	// note using Printf not Println
-	fmt.Printf("type conversions Type: %T Value: %v\n", f, f)
-	fmt.Printf("type conversions Type: %T Value: %v\n", u, u)
+	fmt.Fprintf(w, "type conversions Type: %T Value: %v\n", f, f)
+	fmt.Fprintf(w, "type conversions Type: %T Value: %v\n", u, u)
 
 	// go has type inference
 	ii := 42           // int
 	fi := 3.142        // float64
 	gi := 0.867 + 0.5i // complex128
-	fmt.Printf("type infer %T %v\n", ii, ii)
-	fmt.Printf("type infer again %T %v\n", fi, fi)
-	fmt.Printf("type infer again v2 %T %v\n", gi, gi)
+	fmt.Fprintf(w, "type infer %T %v\n", ii, ii)
+	fmt.Fprintf(w, "type infer again %T %v\n", fi, fi)
+	fmt.Fprintf(w, "type infer again v2 %T %v\n", gi, gi)
 }
 
 func needInt(x int) int { return x*10 + 1 }
@@ -118,8 +140,8 @@ func needFloat(x float64) float64 {
 	return x * 0.1
 }
 
-func numericConstants() {
-	fmt.Print("\n \nNumeric constants \n \n")
+func numericConstants(w io.Writer) {
+	fmt.Fprint(w, "\n \nNumeric constants \n \n")
 	const (
 		// Create a huge number by shifting a 1 bit left 100 places.
 		// In other words, the binary number that is 1 followed by 100 zeroes.
@@ -127,19 +149,19 @@ func numericConstants() {
 		// Shift it right again 99 places, so we end up with 1<<1, or 2.
 		Small = Big >> 99
 	)
-	fmt.Println(needInt(Small))
-	fmt.Println(needFloat(Small))
-	fmt.Println(needFloat(Big))
+	fmt.Fprintln(w, needInt(Small))
+	fmt.Fprintln(w, needFloat(Small))
+	fmt.Fprintln(w, needFloat(Big))
 }
 
-func loopingThereCanOnlyBeOne() {
+func loopingThereCanOnlyBeOne(w io.Writer) {
 	// go only has for loops
-	fmt.Print("\n \nFor loops \n \n")
+	fmt.Fprint(w, "\n \nFor loops \n \n")
 	sum := 0
 	for i := 0; i < 10; i++ {
 		sum += i
 	}
-	fmt.Println(sum)
+	fmt.Fprintln(w, sum)
 
 	// do not use () for the loops. actually an error
 
@@ -148,7 +170,7 @@ func loopingThereCanOnlyBeOne() {
 	for sum2 < 1000 {
 		sum2 += sum2
 	}
-	fmt.Println(sum2)
+	fmt.Fprintln(w, sum2)
 
 	// init and post parts are optional. note tool chain removes the ;
 	// could be      for ; sum3 < 1000 ; {
@@ -156,7 +178,7 @@ func loopingThereCanOnlyBeOne() {
 	for sum3 < 1000 {
 		sum3 += sum3
 	}
-	fmt.Println(sum3)
+	fmt.Fprintln(w, sum3)
 
 	/* infinite loop is
 		for {
@@ -164,20 +186,20 @@ func loopingThereCanOnlyBeOne() {
 	*/
 }
 
-func ifStmt() {
+func ifStmt(w io.Writer) {
 	// don't use (). similar to for loop
 	if 1 < 2 {
-		fmt.Println("1 is less than 2")
+		fmt.Fprintln(w, "1 is less than 2")
 	}
 
-	fmt.Println(
+	fmt.Fprintln(w,
 		pow(3, 2, 10),
 		pow(3, 3, 20),
 	)
 
 	if 2 < 1 {
 	} else {
-		fmt.Println("2 is not less than 1")
+		fmt.Fprintln(w, "2 is not less than 1")
 	}
 }
 
@@ -190,52 +212,53 @@ func pow(x, n, lim float64) float64 {
 	return lim
 }
 
-func switchStmt() {
+func switchStmt(env *lesson.Env) {
+	w := env.Out
 	/*
 		Go's switch is like the one in C, C++, Java, JavaScript, and PHP, except that Go only runs the selected case,
 		not all the cases that follow.  In effect, the break statement that is needed at the end of each case in those
 		languages is provided automatically in Go. Another important difference is that Go's switch cases need not be constants,
 		and the values involved need not be integers
 	*/
-	fmt.Print("Go runs on ")
+	fmt.Fprint(w, "Go runs on ")
 	switch os := runtime.GOOS; os {
 	case "darwin":
-		fmt.Println("OS X.")
+		fmt.Fprintln(w, "OS X.")
 	case "linux":
-		fmt.Println("Linux.")
+		fmt.Fprintln(w, "Linux.")
 	default:
 		// freebsd, openbsd,
 		// plan9, windows...
-		fmt.Printf("%s.\n", os)
+		fmt.Fprintf(w, "%s.\n", os)
 	}
 
 	// by the way go will try to autoimport the packages you need after saving
 
-	fmt.Println("When's Saturday?")
-	today := time.Now().Weekday()
+	fmt.Fprintln(w, "When's Saturday?")
+	today := env.Clock.Now().Weekday() // try tour run --now thursday switchStmt
 	switch time.Saturday {
 	case today + 0:
-		fmt.Println("Today.")
+		fmt.Fprintln(w, "Today.")
 	case today + 1:
-		fmt.Println("Tomorrow.")
+		fmt.Fprintln(w, "Tomorrow.")
 	case today + 2:
-		fmt.Println("In two days.")
+		fmt.Fprintln(w, "In two days.")
 	default:
-		fmt.Println("Too far away.")
+		fmt.Fprintln(w, "Too far away.")
 	}
 
 	// switch statements can omit the condition. ie just switch {}. is a switch true
 }
 
-func deferStmt() {
+func deferStmt(w io.Writer) {
 	// defer will execute after surronding function returns
-	defer fmt.Println("world")
-	fmt.Println("hello")
+	defer fmt.Fprintln(w, "world")
+	fmt.Fprintln(w, "hello")
 
 	// defered function calls are put on a stack. LIFO
-	fmt.Println("counting")
+	fmt.Fprintln(w, "counting")
 	for i := 0; i < 5; i++ {
-		defer fmt.Println(i)
+		defer fmt.Fprintln(w, i)
 	}
-	fmt.Println("done")
+	fmt.Fprintln(w, "done")
 }
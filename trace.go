@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime/trace"
+	"strings"
+
+	"github.com/DaveM7788/tourOfGo/chantrace"
+	"github.com/DaveM7788/tourOfGo/concurrency"
+)
+
+// traceCmd runs the traced copy of a concurrency lesson and draws what the
+// goroutines did
+func traceCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("trace", flag.ContinueOnError)
+	format := fs.String("format", "text", "output `format`: text, mermaid or svg")
+	runtimeTrace := fs.String("runtime-trace", "", "also write a runtime/trace to `file` for go tool trace")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("give one lesson to trace: %s", strings.Join(concurrency.TracedNames(), ", "))
+	}
+	run, ok := concurrency.Traced(fs.Arg(0))
+	if !ok {
+		return fmt.Errorf("%q has no traced version. try one of %s", fs.Arg(0), strings.Join(concurrency.TracedNames(), ", "))
+	}
+
+	render := map[string]func(io.Writer, []chantrace.Event) error{
+		"text":    chantrace.WriteTimeline,
+		"mermaid": chantrace.WriteMermaid,
+		"svg":     chantrace.WriteSVG,
+	}[*format]
+	if render == nil {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	if *runtimeTrace != "" {
+		f, err := os.Create(*runtimeTrace)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := trace.Start(f); err != nil {
+			return err
+		}
+		defer trace.Stop()
+	}
+
+	tr := chantrace.New(context.Background())
+	run(tr)
+	tr.Stop()
+	return render(w, tr.Events())
+}
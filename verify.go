@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DaveM7788/tourOfGo/clock"
+	"github.com/DaveM7788/tourOfGo/lesson"
+)
+
+// the expected output of every lesson lives in golden/<topic>/<lesson>.golden
+// and gets compiled into the binary so verify works from any directory
+//
+//go:embed golden
+var goldenFS embed.FS
+
+func verifyCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	topic := fs.String("topic", "", "only verify lessons in this `topic`")
+	update := fs.Bool("update", false, "rewrite missing or failing golden files under `golden/` in the current directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// verify defaults to everything, unlike run
+	all := *topic == "" && fs.NArg() == 0
+	lessons, err := selectLessons(all, *topic, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, l := range lessons {
+		got := capture(l)
+		err := check(l, got)
+		if err == nil {
+			fmt.Fprintf(w, "ok    %s/%s\n", l.Topic, l.Name)
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "FAIL  %s/%s\n\t%s\n", l.Topic, l.Name, strings.ReplaceAll(err.Error(), "\n", "\n\t"))
+		if *update {
+			if err := writeGolden(l, got); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "\tupdated %s. put back any {{*}} or {{...}} masks by hand\n", goldenPath(l))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d lessons failed verification", failed, len(lessons))
+	}
+	return nil
+}
+
+// capture runs a lesson on a fake clock and returns everything it wrote.
+// the clock starts at the playground time and the seed is always 1 so time
+// and random number lessons print the same thing on every run
+func capture(l lesson.Lesson) string {
+	var buf lockedBuffer
+	l.Exec(lesson.FakeEnv(&buf, clock.Playground, 1))
+	return buf.String()
+}
+
+func check(l lesson.Lesson, got string) error {
+	want, err := fs.ReadFile(goldenFS, goldenPath(l))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no golden file %s", goldenPath(l))
+	}
+	if err != nil {
+		return err
+	}
+	return lesson.Match(got, string(want), l.Unordered)
+}
+
+func goldenPath(l lesson.Lesson) string {
+	return path.Join("golden", l.Topic, l.Name+".golden")
+}
+
+func writeGolden(l lesson.Lesson, got string) error {
+	if l.Unordered {
+		lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+		sort.Strings(lines)
+		got = strings.Join(lines, "\n") + "\n"
+	}
+	p := filepath.FromSlash(goldenPath(l))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, []byte(got), 0o644)
+}
+
+// lockedBuffer is a bytes.Buffer that goroutines can share. lessons like
+// simpleGoroutine print from more than one goroutine at once
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
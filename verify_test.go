@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/DaveM7788/tourOfGo/lesson"
+)
+
+// the same as tour verify, so go test catches a lesson drifting from its
+// golden file
+func TestGolden(t *testing.T) {
+	all := lesson.All()
+	if len(all) == 0 {
+		t.Fatal("no lessons registered")
+	}
+	for _, l := range all {
+		t.Run(l.Topic+"/"+l.Name, func(t *testing.T) {
+			if err := check(l, capture(l)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
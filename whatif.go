@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DaveM7788/tourOfGo/whatif"
+)
+
+// whatifCmd lists the broken variants or runs one in its own process and
+// explains what went wrong
+func whatifCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("whatif", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", 2*time.Second, "dump goroutines of a variant still running after this long")
+	raw := fs.Bool("raw", false, "also print the runtime's own output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, v := range whatif.All() {
+			fmt.Fprintf(tw, "%s\t(%s)\t%s\n", v.Name, v.Lesson, v.Description)
+		}
+		return tw.Flush()
+	}
+
+	for i, name := range fs.Args() {
+		v, ok := whatif.Lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown variant %q. run tour whatif to see them", name)
+		}
+		r, err := whatif.Isolate(context.Background(), v, *timeout)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		r.Explain(w)
+		if *raw {
+			fmt.Fprintf(w, "\nraw output:\n%s", r.Stderr)
+		}
+	}
+	return nil
+}
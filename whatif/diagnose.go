@@ -0,0 +1,242 @@
+package whatif
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Goroutine is one goroutine out of a stack dump
+type Goroutine struct {
+	ID    int
+	State string // as the runtime wrote it: chan send, chan receive, select, sleep...
+
+	// where the goroutine is in our code, ie. the first frame that isn't in
+	// the runtime or the standard library
+	Func string
+	File string
+	Line int
+
+	Source string // that line of source, if the file could be found
+	Chan   string // best guess at which channel, from Source
+}
+
+// Stuck reports whether the goroutine is waiting on a channel or lock
+func (g Goroutine) Stuck() bool {
+	for _, p := range []string{"chan ", "select", "sync.", "semacquire"} {
+		if strings.HasPrefix(g.State, p) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// goroutine 1 [chan send]:  or  goroutine 7 [chan receive, 2 minutes]:
+	headerRe = regexp.MustCompile(`^goroutine (\d+)(?: [^\[]*)?\[([^\],]+)[^\]]*\]:$`)
+	// \t/home/you/tourOfGo/concurrency/whatif.go:17 +0x3c  and after a
+	// SIGQUIT there's fp=... sp=... pc=... on the end as well
+	fileRe = regexp.MustCompile(`^\t(.+?):(\d+)(?: .*)?$`)
+
+	sendRe  = regexp.MustCompile(`([\w.\[\]]+)\s*<-`)
+	recvRe  = regexp.MustCompile(`(?:<-\s*|range\s+)([\w.]+)`)
+	chanRes = map[string]*regexp.Regexp{"chan send": sendRe, "chan receive": recvRe}
+)
+
+// parseDump pulls the goroutines out of a fatal error or SIGQUIT dump
+func parseDump(dump string) []Goroutine {
+	var gs []Goroutine
+	var cur *Goroutine
+	var fn string
+	sc := bufio.NewScanner(strings.NewReader(dump))
+	for sc.Scan() {
+		line := sc.Text()
+		if m := headerRe.FindStringSubmatch(line); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			gs = append(gs, Goroutine{ID: id, State: m[2]})
+			cur = &gs[len(gs)-1]
+			continue
+		}
+		if cur == nil || cur.Func != "" {
+			continue
+		}
+		if m := fileRe.FindStringSubmatch(line); m != nil {
+			if userFunc(fn) {
+				cur.Func = shortFunc(fn)
+				cur.File = m[1]
+				cur.Line, _ = strconv.Atoi(m[2])
+			}
+			continue
+		}
+		fn = line
+	}
+
+	for i := range gs {
+		g := &gs[i]
+		g.Source = sourceLine(g.File, g.Line)
+		if re := chanRes[g.State]; re != nil {
+			if m := re.FindStringSubmatch(g.Source); m != nil {
+				g.Chan = m[1]
+			}
+		}
+	}
+	// a SIGQUIT dump includes the runtime's own goroutines too. if any
+	// goroutine is in our code the rest are just noise
+	var ours []Goroutine
+	for _, g := range gs {
+		if g.Func != "" {
+			ours = append(ours, g)
+		}
+	}
+	if len(ours) > 0 {
+		gs = ours
+	}
+
+	// stuck ones first, otherwise keep the runtime's order
+	sort.SliceStable(gs, func(i, j int) bool { return gs[i].Stuck() && !gs[j].Stuck() })
+	return gs
+}
+
+// userFunc reports whether a stack frame is in our code. standard library
+// packages never have a dot in the first part of their path, modules do
+func userFunc(frame string) bool {
+	if strings.HasPrefix(frame, "created by ") || frame == "" {
+		return false
+	}
+	if strings.HasPrefix(frame, "main.") {
+		return true
+	}
+	first, _, _ := strings.Cut(frame, "/")
+	return strings.Contains(first, ".") && strings.Contains(frame, "/")
+}
+
+// shortFunc turns github.com/x/y/concurrency.overfill(...) into
+// concurrency.overfill
+func shortFunc(frame string) string {
+	if i := strings.LastIndexByte(frame, '('); i > 0 {
+		frame = frame[:i]
+	}
+	return frame[strings.LastIndexByte(frame, '/')+1:]
+}
+
+// sourceLine reads one line of a file, or returns "" if it can't
+func sourceLine(file string, line int) string {
+	if file == "" {
+		return ""
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		if n == line {
+			return strings.TrimSpace(sc.Text())
+		}
+	}
+	return ""
+}
+
+// Explain writes a friendly account of what went wrong
+func (r *Report) Explain(w io.Writer) {
+	fmt.Fprintf(w, "%s (a broken %s): %s\n\n", r.Variant.Name, r.Variant.Lesson, r.Variant.Description)
+	if r.Output != "" {
+		fmt.Fprintf(w, "it printed:\n%s\n\n", indent(r.Output))
+	}
+
+	switch {
+	case r.Deadlock:
+		fmt.Fprintln(w, "deadlock! every goroutine was asleep, so the runtime gave up:")
+	case r.TimedOut:
+		fmt.Fprintln(w, "it was still running at the timeout. no deadlock as far as the runtime can tell,")
+		fmt.Fprintln(w, "something is still ticking or sleeping. this is where everything was:")
+	case r.Panicked:
+		fmt.Fprintln(w, "it panicked:")
+		fmt.Fprintln(w, indent(firstLine(r.Stderr)))
+	case r.ExitCode == 0:
+		fmt.Fprintln(w, "it finished fine. nothing to diagnose")
+		return
+	default:
+		fmt.Fprintf(w, "it exited with status %d:\n%s\n", r.ExitCode, indent(r.Stderr))
+		return
+	}
+
+	for _, g := range r.Goroutines {
+		fmt.Fprintf(w, "\ngoroutine %d is %s\n", g.ID, describeState(g.State))
+		if g.Func != "" {
+			fmt.Fprintf(w, "    in %s at %s:%d\n", g.Func, shortPath(g.File), g.Line)
+		}
+		if g.Source != "" {
+			fmt.Fprintf(w, "        %s\n", g.Source)
+		}
+		if hint := hint(g); hint != "" {
+			fmt.Fprintf(w, "    %s\n", hint)
+		}
+	}
+	if len(r.Goroutines) == 0 {
+		fmt.Fprintf(w, "no goroutine dump to read. the raw output was:\n%s\n", indent(r.Stderr))
+	}
+}
+
+func describeState(state string) string {
+	switch state {
+	case "chan send":
+		return "stuck sending on a channel"
+	case "chan receive":
+		return "stuck receiving from a channel"
+	case "chan send (nil chan)", "chan receive (nil chan)":
+		return "stuck on a nil channel, which blocks forever"
+	case "select":
+		return "stuck in a select"
+	case "select (no cases)":
+		return "stuck in an empty select{}, which blocks forever"
+	case "sleep":
+		return "sleeping"
+	case "running", "runnable":
+		return state
+	}
+	return "waiting (" + state + ")"
+}
+
+func hint(g Goroutine) string {
+	ch := g.Chan
+	if ch == "" {
+		ch = "the channel"
+	}
+	switch g.State {
+	case "chan send":
+		return fmt.Sprintf("nothing is left to receive from %s and its buffer (if any) is full", ch)
+	case "chan receive":
+		return fmt.Sprintf("nothing is left to send on %s and it was never closed. a range only ends on close", ch)
+	case "select":
+		return "none of the select's channels will ever be ready"
+	}
+	return ""
+}
+
+// shortPath makes a path relative to the working directory if it is under it
+func shortPath(p string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, p); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return p
+}
+
+func indent(s string) string {
+	s = strings.TrimRight(s, "\n")
+	return "    " + strings.ReplaceAll(s, "\n", "\n    ")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,265 @@
+package whatif
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// the lines the dumps below point at. FILE in a dump becomes this file
+const source = `package concurrency
+func f() {
+	ch <- 3
+	for i := range c {
+	v := <-results
+	select {
+}
+`
+
+// dumps captured from tour whatif and trimmed down. the paths into our code
+// are swapped for FILE, the rest are left as the runtime wrote them
+const (
+	overfillDump = `fatal error: all goroutines are asleep - deadlock!
+
+goroutine 1 [chan send]:
+github.com/DaveM7788/tourOfGo/concurrency.overfill({0x81e3b8, 0xb9a077ee050})
+	FILE:3 +0x65
+github.com/DaveM7788/tourOfGo/whatif.MaybeChild()
+	/root/module/whatif/whatif.go:69 +0x153
+main.main()
+	/root/module/tour.go:56 +0x13
+`
+
+	twoDump = `fatal error: all goroutines are asleep - deadlock!
+
+goroutine 1 [chan receive]:
+main.main()
+	FILE:4 +0x127
+
+goroutine 7 [chan receive, 2 minutes]:
+runtime.gopark(0x0?, 0x0?, 0x0?, 0x0?, 0x0?)
+	/usr/local/go/src/runtime/proc.go:474 +0xca
+runtime.chanrecv1(0xc000020060?, 0x0?)
+	/usr/local/go/src/runtime/chan.go:506 +0x12
+example.com/work.worker(...)
+	FILE:5 +0x1d
+created by example.com/work.start in goroutine 1
+	FILE:6 +0x25
+`
+
+	sigquitDump = `SIGQUIT: quit
+PC=0x410a2e m=0 sigcode=0
+
+goroutine 0 gp=0x843d00 m=0 mp=0x844ac0 [idle]:
+internal/runtime/syscall/linux.Syscall6()
+	/usr/local/go/src/internal/runtime/syscall/linux/asm_linux_amd64.s:36 +0xe fp=0x7ffd94e37d40 sp=0x7ffd94e37d38 pc=0x410a2e
+runtime.mcall()
+	/usr/local/go/src/runtime/asm_amd64.s:463 +0x53 fp=0x7ffd94e386a8 sp=0x7ffd94e38690 pc=0x48adb3
+
+goroutine 1 gp=0x37521d8cc1e0 m=nil [sleep]:
+runtime.gopark(0x5414a8a44c0?, 0x37521d8ca048?, 0xb0?, 0xa4?, 0x6?)
+	/usr/local/go/src/runtime/proc.go:474 +0xca fp=0x37521d961d10 sp=0x37521d961cf0 pc=0x4857aa
+time.Sleep(0x2faf080)
+	/usr/local/go/src/runtime/time.go:368 +0x165 fp=0x37521d961d68 sp=0x37521d961d10 pc=0x4891e5
+github.com/DaveM7788/tourOfGo/concurrency.noboom({0x81e3b8, 0x37521d8ca048})
+	/home/you/tourOfGo/concurrency/whatif.go:79 +0x7e fp=0x37521d961db8 sp=0x37521d961d68 pc=0x59c01e
+main.main()
+	/home/you/tourOfGo/tour.go:56 +0x13 fp=0x37521d961eb8 sp=0x37521d961e90 pc=0x5ee5b3
+
+goroutine 2 gp=0x37521d8cc780 m=nil [force gc (idle)]:
+runtime.gopark(0x0?, 0x0?, 0x0?, 0x0?, 0x0?)
+	/usr/local/go/src/runtime/proc.go:474 +0xca fp=0x37521d904fa8 sp=0x37521d904f88 pc=0x4857aa
+runtime.forcegchelper()
+	/usr/local/go/src/runtime/proc.go:387 +0xb3 fp=0x37521d904fe0 sp=0x37521d904fa8 pc=0x44e6d3
+created by runtime.init.7 in goroutine 1
+	/usr/local/go/src/runtime/proc.go:375 +0x1a
+
+goroutine 9 gp=0x37521d8cd0e0 m=nil [select, 1 minutes]:
+runtime.gopark(0x0?, 0x0?, 0x0?, 0x0?, 0x0?)
+	/usr/local/go/src/runtime/proc.go:474 +0xca fp=0x37521d905f78 sp=0x37521d905f58 pc=0x4857aa
+runtime.selectgo(0x37521d905fa0, 0x37521d905f90, 0x0?, 0x0, 0x0?, 0x1)
+	/usr/local/go/src/runtime/select.go:351 +0x837 fp=0x37521d905f80 sp=0x37521d905f78 pc=0x45ab37
+github.com/DaveM7788/tourOfGo/concurrency.noboom.func1()
+	FILE:6 +0x7e fp=0x37521d905fc8 sp=0x37521d905f80 pc=0x59c0de
+created by github.com/DaveM7788/tourOfGo/concurrency.noboom in goroutine 1
+	FILE:2 +0x5e fp=0x37521d905fe0 sp=0x37521d905fc8 pc=0x59c09e
+`
+
+	runtimeOnlyDump = `SIGQUIT: quit
+
+goroutine 2 [force gc (idle)]:
+runtime.forcegchelper()
+	/usr/local/go/src/runtime/proc.go:387 +0xb3
+created by runtime.init.7 in goroutine 1
+	/usr/local/go/src/runtime/proc.go:375 +0x1a
+`
+)
+
+// sourceFile writes source out and returns its path
+func sourceFile(t *testing.T) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "whatif.go")
+	if err := os.WriteFile(file, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestParseDump(t *testing.T) {
+	file := sourceFile(t)
+	for _, tt := range []struct {
+		name string
+		dump string
+		want []Goroutine
+	}{
+		{"overfill", overfillDump, []Goroutine{
+			{ID: 1, State: "chan send", Func: "concurrency.overfill", File: file, Line: 3, Source: "ch <- 3", Chan: "ch"},
+		}},
+		{"two goroutines", twoDump, []Goroutine{
+			{ID: 1, State: "chan receive", Func: "main.main", File: file, Line: 4, Source: "for i := range c {", Chan: "c"},
+			{ID: 7, State: "chan receive", Func: "work.worker", File: file, Line: 5, Source: "v := <-results", Chan: "results"},
+		}},
+		// the runtime's goroutines go, the stuck select comes before the
+		// sleep, and a file that isn't there just has no source
+		{"sigquit", sigquitDump, []Goroutine{
+			{ID: 9, State: "select", Func: "concurrency.noboom.func1", File: file, Line: 6, Source: "select {"},
+			{ID: 1, State: "sleep", Func: "concurrency.noboom", File: "/home/you/tourOfGo/concurrency/whatif.go", Line: 79},
+		}},
+		{"runtime only", runtimeOnlyDump, []Goroutine{
+			{ID: 2, State: "force gc (idle)"},
+		}},
+		{"no dump", "panic: oops\n", nil},
+		{"empty", "", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDump(strings.ReplaceAll(tt.dump, "FILE", file))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserFunc(t *testing.T) {
+	for _, tt := range []struct {
+		frame string
+		want  bool
+	}{
+		{"github.com/DaveM7788/tourOfGo/concurrency.overfill({0x81e3b8, 0xb9a077ee050})", true},
+		{"example.com/work.worker(...)", true},
+		{"main.main()", true},
+		{"main.func1()", true},
+		{"runtime.gopark(0x0?, 0x0?)", false},
+		{"internal/runtime/syscall/linux.Syscall6()", false},
+		{"sync.(*WaitGroup).Wait(0xc000012345)", false},
+		{"net/http.(*Server).Serve(0xc0000)", false},
+		// a dot in the first part but no package path after it
+		{"gopkg.in(0x1)", false},
+		{"created by example.com/work.start in goroutine 1", false},
+		{"", false},
+	} {
+		if got := userFunc(tt.frame); got != tt.want {
+			t.Errorf("userFunc(%q) = %v, want %v", tt.frame, got, tt.want)
+		}
+	}
+}
+
+func TestShortFunc(t *testing.T) {
+	for _, tt := range []struct{ frame, want string }{
+		{"github.com/DaveM7788/tourOfGo/concurrency.overfill({0x81e3b8, 0xb9a077ee050})", "concurrency.overfill"},
+		{"github.com/DaveM7788/tourOfGo/concurrency.noboom.func1()", "concurrency.noboom.func1"},
+		{"example.com/work.(*Pool).run(...)", "work.(*Pool).run"},
+		{"main.main()", "main.main"},
+		{"main.main", "main.main"},
+	} {
+		if got := shortFunc(tt.frame); got != tt.want {
+			t.Errorf("shortFunc(%q) = %q, want %q", tt.frame, got, tt.want)
+		}
+	}
+}
+
+func TestHint(t *testing.T) {
+	for _, tt := range []struct {
+		g    Goroutine
+		want string
+	}{
+		{Goroutine{State: "chan send", Chan: "ch"}, "nothing is left to receive from ch and its buffer (if any) is full"},
+		{Goroutine{State: "chan send"}, "nothing is left to receive from the channel and its buffer (if any) is full"},
+		{Goroutine{State: "chan receive", Chan: "c"}, "nothing is left to send on c and it was never closed. a range only ends on close"},
+		{Goroutine{State: "select"}, "none of the select's channels will ever be ready"},
+		{Goroutine{State: "sleep"}, ""},
+		{Goroutine{State: "chan send (nil chan)"}, ""},
+	} {
+		if got := hint(tt.g); got != tt.want {
+			t.Errorf("hint(%+v) = %q, want %q", tt.g, got, tt.want)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	file := sourceFile(t)
+	overfill := Variant{Name: "overfill", Lesson: "bufferedChan", Description: "a third send into a channel with room for two"}
+	deadlock := strings.ReplaceAll(overfillDump, "FILE", file)
+	for _, tt := range []struct {
+		name string
+		r    Report
+		want string
+	}{
+		{"deadlock", Report{Output: "1\n2\n", Stderr: deadlock, Deadlock: true, ExitCode: 2, Goroutines: parseDump(deadlock)},
+			`overfill (a broken bufferedChan): a third send into a channel with room for two
+
+it printed:
+    1
+    2
+
+deadlock! every goroutine was asleep, so the runtime gave up:
+
+goroutine 1 is stuck sending on a channel
+    in concurrency.overfill at FILE:3
+        ch <- 3
+    nothing is left to receive from ch and its buffer (if any) is full
+`},
+		{"timed out", Report{TimedOut: true, ExitCode: 2, Goroutines: []Goroutine{{ID: 1, State: "sleep"}}},
+			`overfill (a broken bufferedChan): a third send into a channel with room for two
+
+it was still running at the timeout. no deadlock as far as the runtime can tell,
+something is still ticking or sleeping. this is where everything was:
+
+goroutine 1 is sleeping
+`},
+		{"panicked", Report{Stderr: "panic: send on closed channel\ngoroutine 1 [running]:\n", Panicked: true, ExitCode: 2},
+			`overfill (a broken bufferedChan): a third send into a channel with room for two
+
+it panicked:
+    panic: send on closed channel
+no goroutine dump to read. the raw output was:
+    panic: send on closed channel
+    goroutine 1 [running]:
+`},
+		{"fine", Report{Output: "ok\n"},
+			`overfill (a broken bufferedChan): a third send into a channel with room for two
+
+it printed:
+    ok
+
+it finished fine. nothing to diagnose
+`},
+		{"exit status", Report{Stderr: "whatif: unknown variant \"x\"\n", ExitCode: 3},
+			`overfill (a broken bufferedChan): a third send into a channel with room for two
+
+it exited with status 3:
+    whatif: unknown variant "x"
+`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.r.Variant = overfill
+			var b strings.Builder
+			tt.r.Explain(&b)
+			if want := strings.ReplaceAll(tt.want, "FILE", file); b.String() != want {
+				t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package whatif
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Report is what happened when a variant ran
+type Report struct {
+	Variant  Variant
+	Output   string // what it printed before it got stuck
+	Stderr   string // the raw fatal error or goroutine dump
+	Deadlock bool   // the runtime noticed every goroutine was asleep
+	TimedOut bool   // still going when the timeout hit, so we dumped its goroutines
+	Panicked bool
+	ExitCode int
+
+	// Goroutines are the goroutines from the dump, stuck ones first
+	Goroutines []Goroutine
+}
+
+// Isolate runs v in a child copy of the current executable and waits up to
+// timeout for it. the runtime notices a full deadlock by itself. anything
+// still running after timeout gets a SIGQUIT, which makes go print every
+// goroutine's stack before exiting (on windows it is just killed)
+func Isolate(ctx context.Context, v Variant, timeout time.Duration) (*Report, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, exe)
+	cmd.Env = append(os.Environ(), childEnv+"="+v.Name, "GOTRACEBACK=all")
+	cmd.Cancel = func() error { return cmd.Process.Signal(dumpSignal) }
+	// if it ignores the signal somehow, kill it for real
+	cmd.WaitDelay = 2 * time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	err = cmd.Run()
+	r := &Report{
+		Variant:  v,
+		Output:   stdout.String(),
+		Stderr:   stderr.String(),
+		TimedOut: ctx.Err() == context.DeadlineExceeded,
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		r.ExitCode = exitErr.ExitCode()
+	case err != nil && !r.TimedOut:
+		return nil, err
+	}
+	r.Deadlock = strings.Contains(r.Stderr, "all goroutines are asleep - deadlock!")
+	r.Panicked = strings.HasPrefix(r.Stderr, "panic: ")
+	r.Goroutines = parseDump(r.Stderr)
+	return r, nil
+}
@@ -0,0 +1,44 @@
+package whatif_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/DaveM7788/tourOfGo/concurrency" // registers the variants
+	"github.com/DaveM7788/tourOfGo/whatif"
+)
+
+// Isolate re-runs the test binary, so it has to act as the child too
+func TestMain(m *testing.M) {
+	whatif.MaybeChild()
+	os.Exit(m.Run())
+}
+
+func TestIsolateOverfill(t *testing.T) {
+	v, ok := whatif.Lookup("overfill")
+	if !ok {
+		t.Fatal("no overfill variant")
+	}
+	r, err := whatif.Isolate(context.Background(), v, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// under -race the race detector keeps a thread of its own going, so the
+	// runtime never sees the deadlock and the timeout's dump catches it
+	// instead. the diagnosis comes out the same either way
+	if !(r.Deadlock || r.TimedOut) || r.Panicked || r.ExitCode != 2 {
+		t.Errorf("deadlock %v, timed out %v, panicked %v, exit %d\n%s", r.Deadlock, r.TimedOut, r.Panicked, r.ExitCode, r.Stderr)
+	}
+	if r.Output != "" {
+		t.Errorf("printed %q before the third send", r.Output)
+	}
+	if len(r.Goroutines) != 1 {
+		t.Fatalf("goroutines = %+v", r.Goroutines)
+	}
+	g := r.Goroutines[0]
+	if g.State != "chan send" || g.Func != "concurrency.overfill" || g.Source != "ch <- 3" || g.Chan != "ch" {
+		t.Errorf("goroutine = %+v", g)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package whatif
+
+import "os"
+
+// there is no SIGQUIT on windows, so a timed out variant is just killed and
+// there won't be a goroutine dump to diagnose
+var dumpSignal = os.Kill
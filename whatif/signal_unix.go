@@ -0,0 +1,8 @@
+//go:build unix
+
+package whatif
+
+import "syscall"
+
+// SIGQUIT makes the go runtime dump every goroutine's stack and exit
+var dumpSignal = syscall.SIGQUIT
@@ -0,0 +1,71 @@
+// Package whatif runs the broken versions of lessons, the ones the comments
+// warn about, like overfilling a buffered channel. each variant runs in its
+// own process so a deadlock only kills that process. the runtime's fatal
+// error or a goroutine dump is then read back and turned into a diagnosis of
+// which goroutines got stuck where
+package whatif
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Variant is a lesson with something deliberately wrong in it
+type Variant struct {
+	Name        string // what you type after `tour whatif`
+	Lesson      string // the lesson it is a broken version of
+	Description string // what was changed
+	Run         func(w io.Writer)
+}
+
+var registry = map[string]Variant{}
+
+// Register adds v to the registry. it panics on a duplicate or incomplete
+// variant, same as lesson.Register
+func Register(v Variant) {
+	if v.Name == "" || v.Run == nil {
+		panic(fmt.Sprintf("whatif: Register called with incomplete variant %q", v.Name))
+	}
+	if _, dup := registry[v.Name]; dup {
+		panic("whatif: Register called twice for variant " + v.Name)
+	}
+	registry[v.Name] = v
+}
+
+// Lookup finds a variant by name
+func Lookup(name string) (Variant, bool) {
+	v, ok := registry[name]
+	return v, ok
+}
+
+// All returns every variant sorted by name
+func All() []Variant {
+	all := make([]Variant, 0, len(registry))
+	for _, v := range registry {
+		all = append(all, v)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// the child process finds out which variant to run from this
+const childEnv = "TOUR_WHATIF_CHILD"
+
+// MaybeChild must be called at the very start of main. in the child process
+// it runs the variant and exits, in the parent it does nothing. it's the
+// same trick the testing package uses to run a test binary as a subprocess
+func MaybeChild() {
+	name := os.Getenv(childEnv)
+	if name == "" {
+		return
+	}
+	v, ok := Lookup(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "whatif: unknown variant %q\n", name)
+		os.Exit(3)
+	}
+	v.Run(os.Stdout)
+	os.Exit(0)
+}
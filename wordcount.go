@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/DaveM7788/tourOfGo/wordcount"
+)
+
+// wordcountCmd counts the words in files, or stdin when there aren't any
+func wordcountCmd(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("wordcount", flag.ContinueOnError)
+	// the same for both. 0 turns the table off, -1 shows everything
+	top := fs.Int("top", 10, "show the n most common words. -1 shows all of them")
+	bigrams := fs.Int("bigrams", 0, "also show the n most common pairs of words. -1 shows all of them")
+	format := fs.String("format", "text", "text, json or csv")
+	parallel := fs.Int("parallel", 1, "count with this many goroutines. 0 means one per CPU")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var write func(wordcount.Report, io.Writer) error
+	switch *format {
+	case "text":
+		write = wordcount.Report.WriteText
+	case "json":
+		write = wordcount.Report.WriteJSON
+	case "csv":
+		write = wordcount.Report.WriteCSV
+	default:
+		return fmt.Errorf("unknown format %q. use text, json or csv", *format)
+	}
+
+	var readers []io.Reader
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		readers = append(readers, f)
+	}
+	if len(readers) == 0 {
+		readers = append(readers, os.Stdin)
+	}
+	// MultiReader would run the last line of one file into the first of the next
+	total := wordcount.NewCounts()
+	for _, r := range readers {
+		var c *wordcount.Counts
+		var err error
+		if *parallel == 1 {
+			c, err = wordcount.Count(r)
+		} else {
+			c, err = wordcount.CountParallel(context.Background(), r, *parallel)
+		}
+		if err != nil {
+			return err
+		}
+		total.Merge(c)
+	}
+	return write(total.Report(*top, *bigrams), w)
+}
@@ -0,0 +1,91 @@
+package wordcount
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Report is the part of Counts worth writing out: the totals plus the top
+// words and bigrams
+type Report struct {
+	Lines   int             `json:"lines"`
+	Words   int             `json:"words"`
+	Unique  int             `json:"unique"`
+	Runes   int             `json:"runes"`
+	Bytes   int             `json:"bytes"`
+	Top     []Entry[string] `json:"top"`
+	Bigrams []Entry[string] `json:"bigrams,omitempty"`
+}
+
+// Report keeps the top n words and the top bigrams bigrams. for both, 0
+// keeps none and a negative number keeps them all
+func (c *Counts) Report(n, bigrams int) Report {
+	r := Report{Lines: c.Lines, Words: c.Words, Unique: len(c.Freq), Runes: c.Runes, Bytes: c.Bytes}
+	if n != 0 {
+		r.Top = c.Top(n)
+	}
+	if bigrams != 0 {
+		for _, e := range c.TopBigrams(bigrams) {
+			r.Bigrams = append(r.Bigrams, Entry[string]{e.Key.String(), e.Count})
+		}
+	}
+	return r
+}
+
+// MarshalJSON writes an entry as {"key": "go", "count": 3}
+func (e Entry[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Key   T   `json:"key"`
+		Count int `json:"count"`
+	}{e.Key, e.Count})
+}
+
+// WriteText writes the report as a couple of aligned tables
+func (r Report) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%d lines, %d words (%d different), %d runes, %d bytes\n", r.Lines, r.Words, r.Unique, r.Runes, r.Bytes)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if len(r.Top) > 0 {
+		fmt.Fprintln(tw)
+		for _, e := range r.Top {
+			fmt.Fprintf(tw, "%d\t%s\n", e.Count, e.Key)
+		}
+	}
+	if len(r.Bigrams) > 0 {
+		fmt.Fprintln(tw)
+		for _, e := range r.Bigrams {
+			fmt.Fprintf(tw, "%d\t%s\n", e.Count, e.Key)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as one JSON object
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes kind,key,count rows. the totals come first with kind total
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"kind", "key", "count"})
+	for _, t := range []struct {
+		key string
+		n   int
+	}{{"lines", r.Lines}, {"words", r.Words}, {"unique", r.Unique}, {"runes", r.Runes}, {"bytes", r.Bytes}} {
+		cw.Write([]string{"total", t.key, strconv.Itoa(t.n)})
+	}
+	for _, e := range r.Top {
+		cw.Write([]string{"word", e.Key, strconv.Itoa(e.Count)})
+	}
+	for _, e := range r.Bigrams {
+		cw.Write([]string{"bigram", e.Key, strconv.Itoa(e.Count)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
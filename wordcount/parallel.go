@@ -0,0 +1,74 @@
+package wordcount
+
+import (
+	"context"
+	"io"
+	"runtime"
+
+	"github.com/DaveM7788/tourOfGo/pipeline"
+)
+
+// how many lines each worker takes at a time
+const batchSize = 1024
+
+type line struct {
+	text string
+	last bool
+}
+
+// CountParallel is Count spread over workers goroutines, the channelsChan
+// sum grown up. one goroutine reads lines and batches them, the batches are
+// fanned out to the workers, each counts into its own Counts and the partial
+// counts are fanned back in and merged. no map is ever shared, so no locks.
+// bigrams stop at the end of a line, which is what lets a batch be counted
+// without knowing what came before it. workers <= 0 means runtime.NumCPU()
+func CountParallel(ctx context.Context, r io.Reader, workers int) (*Counts, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan line)
+	// buffered so the reader can always hand its error over and exit, even
+	// when we've given up and nobody is going to receive it
+	errc := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		errc <- eachLine(r, func(text string, last bool) bool {
+			select {
+			case lines <- line{text, last}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	batches := pipeline.FanOut(ctx, pipeline.Batch(ctx, lines, batchSize), workers)
+	partials := make([]<-chan *Counts, len(batches))
+	for i, b := range batches {
+		partials[i] = pipeline.Map(ctx, b, func(ls []line) *Counts {
+			c := NewCounts()
+			for _, l := range ls {
+				c.addLine(l.text, l.last)
+			}
+			return c
+		})
+	}
+
+	total := NewCounts()
+	for c := range pipeline.FanIn(ctx, partials...) {
+		total.Merge(c)
+	}
+	// when cancelled the stages stop without waiting for the reader, which
+	// may be stuck in a Read. so don't wait for its error either
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// otherwise every line was sent, so the reader is finishing up
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	return total, nil
+}
@@ -0,0 +1,183 @@
+// Package wordcount is the word frequency exercise the maps lessons stop
+// short of. it reads any io.Reader, the way readersRead does, and counts
+// words in a map[string]int
+package wordcount
+
+import (
+	"bufio"
+	"cmp"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Counts is everything counted from one text
+type Counts struct {
+	Lines, Words, Runes, Bytes int
+
+	// Freq maps each word, lowercased, to how often it appears
+	Freq map[string]int
+
+	// Bigrams counts pairs of words next to each other on the same line
+	Bigrams map[Bigram]int
+}
+
+// Bigram is two words in a row
+type Bigram [2]string
+
+func (b Bigram) String() string {
+	return b[0] + " " + b[1]
+}
+
+// NewCounts makes an empty Counts ready to Add to
+func NewCounts() *Counts {
+	return &Counts{Freq: make(map[string]int), Bigrams: make(map[Bigram]int)}
+}
+
+// Words splits s into words. a word is a run of letters and digits, so
+// "hello 世界" is two words and "hello, world!" drops the punctuation.
+// apostrophes and hyphens inside a word are kept, don't and well-known are
+// one word each. go's strings.Fields only splits on spaces, and the run of
+// han characters has none
+func Words(s string) []string {
+	var words []string
+	start := -1
+	for i, r := range s {
+		if inWord(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		// joiners only count if there's more word straight after
+		if start >= 0 && isJoiner(r) {
+			next, _ := utf8.DecodeRuneInString(s[i+utf8.RuneLen(r):])
+			if inWord(next) {
+				continue
+			}
+		}
+		if start >= 0 {
+			words = append(words, s[start:i])
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+func inWord(r rune) bool {
+	// marks are the accents etc. that combine with the letter before them
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.M, r)
+}
+
+func isJoiner(r rune) bool {
+	return r == '\'' || r == '’' || r == '-'
+}
+
+// AddLine counts one line of text, without its newline
+func (c *Counts) AddLine(line string) {
+	c.Lines++
+	c.Bytes += len(line) + 1
+	c.Runes += utf8.RuneCountInString(line) + 1
+	prev := ""
+	for _, w := range Words(line) {
+		w = strings.ToLower(w)
+		c.Words++
+		c.Freq[w]++
+		if prev != "" {
+			c.Bigrams[Bigram{prev, w}]++
+		}
+		prev = w
+	}
+}
+
+// Merge adds everything counted in o to c
+func (c *Counts) Merge(o *Counts) {
+	c.Lines += o.Lines
+	c.Words += o.Words
+	c.Runes += o.Runes
+	c.Bytes += o.Bytes
+	for w, n := range o.Freq {
+		c.Freq[w] += n
+	}
+	for b, n := range o.Bigrams {
+		c.Bigrams[b] += n
+	}
+}
+
+// Count reads r to the end and counts it
+func Count(r io.Reader) (*Counts, error) {
+	c := NewCounts()
+	err := eachLine(r, func(line string, last bool) bool {
+		c.addLine(line, last)
+		return true
+	})
+	return c, err
+}
+
+func (c *Counts) addLine(line string, last bool) {
+	c.AddLine(line)
+	if last {
+		// there was no newline to count
+		c.Bytes--
+		c.Runes--
+	}
+}
+
+// eachLine calls f for every line in r until f returns false. last is true
+// when the final line had no newline on the end
+func eachLine(r io.Reader, f func(line string, last bool) bool) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if len(line) > 0 {
+			trimmed := strings.TrimSuffix(line, "\n")
+			if !f(trimmed, trimmed == line) {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Entry is a word or bigram and how many times it came up
+type Entry[T any] struct {
+	Key   T
+	Count int
+}
+
+// Top returns the n most common words, most common first and ties in
+// alphabetical order. n <= 0 returns them all
+func (c *Counts) Top(n int) []Entry[string] {
+	return top(c.Freq, n, strings.Compare)
+}
+
+// TopBigrams is Top for pairs of words
+func (c *Counts) TopBigrams(n int) []Entry[Bigram] {
+	return top(c.Bigrams, n, func(a, b Bigram) int {
+		return cmp.Or(strings.Compare(a[0], b[0]), strings.Compare(a[1], b[1]))
+	})
+}
+
+func top[T comparable](m map[T]int, n int, compare func(a, b T) int) []Entry[T] {
+	es := make([]Entry[T], 0, len(m))
+	for k, v := range m {
+		es = append(es, Entry[T]{k, v})
+	}
+	slices.SortFunc(es, func(a, b Entry[T]) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), compare(a.Key, b.Key))
+	})
+	if n > 0 && n < len(es) {
+		es = es[:n]
+	}
+	return es
+}
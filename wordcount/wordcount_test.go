@@ -0,0 +1,136 @@
+package wordcount
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestWords(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"hello 世界", []string{"hello", "世界"}},
+		{"hello, world!", []string{"hello", "world"}},
+		{"don't well-known -x- 'quoted'", []string{"don't", "well-known", "x", "quoted"}},
+		{"café naïve 42", []string{"café", "naïve", "42"}},
+	} {
+		if got := Words(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	c, err := Count(strings.NewReader("The cat\nthe cat sat\nthe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Lines != 3 || c.Words != 6 || c.Bytes != 23 {
+		t.Errorf("got %d lines, %d words, %d bytes. want 3, 6, 23", c.Lines, c.Words, c.Bytes)
+	}
+	want := []Entry[string]{{"the", 3}, {"cat", 2}}
+	if got := c.Top(2); !reflect.DeepEqual(got, want) {
+		t.Errorf("Top(2) = %v, want %v", got, want)
+	}
+	// bigrams don't cross lines, so there's no "cat the"
+	if n := c.Bigrams[Bigram{"cat", "the"}]; n != 0 {
+		t.Errorf("counted %d bigrams across a line break", n)
+	}
+}
+
+// 0 keeps none and a negative number keeps everything, for words and
+// bigrams alike
+func TestReport(t *testing.T) {
+	c, err := Count(strings.NewReader("The cat\nthe cat sat\nthe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		n, bigrams      int
+		top, topBigrams int
+	}{
+		{0, 0, 0, 0},
+		{1, 1, 1, 1},
+		{2, 0, 2, 0},
+		{0, 2, 0, 2},
+		{-1, -1, 3, 2},
+		{10, 10, 3, 2},
+	} {
+		r := c.Report(tt.n, tt.bigrams)
+		if len(r.Top) != tt.top || len(r.Bigrams) != tt.topBigrams {
+			t.Errorf("Report(%d, %d) kept %d words and %d bigrams, want %d and %d", tt.n, tt.bigrams, len(r.Top), len(r.Bigrams), tt.top, tt.topBigrams)
+		}
+		if r.Words != 6 || r.Unique != 3 {
+			t.Errorf("Report(%d, %d) totals %+v", tt.n, tt.bigrams, r)
+		}
+	}
+	if got := c.Report(1, 1); got.Top[0] != (Entry[string]{"the", 3}) || got.Bigrams[0] != (Entry[string]{"the cat", 2}) {
+		t.Errorf("Report(1, 1) = %v %v", got.Top, got.Bigrams)
+	}
+}
+
+func TestCountParallelMatchesCount(t *testing.T) {
+	text := strings.Repeat("the quick brown fox\njumps over the lazy dog\n", 3000) + "no newline"
+	want, err := Count(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{1, 2, 7} {
+		// one byte at a time shakes out anything that assumes whole lines
+		got, err := CountParallel(context.Background(), iotest.OneByteReader(strings.NewReader(text)), workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%d workers: counts differ from Count", workers)
+		}
+	}
+}
+
+func TestCountParallelReadError(t *testing.T) {
+	boom := errors.New("boom")
+	r := io.MultiReader(strings.NewReader("some words\n"), iotest.ErrReader(boom))
+	if _, err := CountParallel(context.Background(), r, 2); err != boom {
+		t.Errorf("got %v, want %v", err, boom)
+	}
+}
+
+// endless never runs out of lines
+type endless struct{}
+
+func (endless) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = "word \n"[i%6]
+	}
+	return len(p), nil
+}
+
+// run with -race. cancelling used to race on the reader's error
+func TestCountParallelCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	done := make(chan error)
+	go func() {
+		_, err := CountParallel(ctx, endless{}, 4)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("CountParallel didn't stop after cancel")
+	}
+}